@@ -4,9 +4,12 @@
 package teleportermessenger
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -22,6 +25,7 @@ const (
 	MessageExecuted
 	RelayerRewardsRedeemed
 	ReceiptReceived
+	BlockchainIDInitialized
 
 	sendCrossChainMessageStr    = "SendCrossChainMessage"
 	receiveCrossChainMessageStr = "ReceiveCrossChainMessage"
@@ -30,6 +34,7 @@ const (
 	messageExecutedStr          = "MessageExecuted"
 	relayerRewardsRedeemedStr   = "RelayerRewardsRedeemed"
 	receiptReceivedStr          = "ReceiptReceived"
+	blockchainIDInitializedStr  = "BlockchainIDInitialized"
 	unknownStr                  = "Unknown"
 )
 
@@ -50,6 +55,8 @@ func (e Event) String() string {
 		return relayerRewardsRedeemedStr
 	case ReceiptReceived:
 		return receiptReceivedStr
+	case BlockchainIDInitialized:
+		return blockchainIDInitializedStr
 	default:
 		return unknownStr
 	}
@@ -72,6 +79,8 @@ func ToEvent(e string) (Event, error) {
 		return RelayerRewardsRedeemed, nil
 	case strings.ToLower(receiptReceivedStr):
 		return ReceiptReceived, nil
+	case strings.ToLower(blockchainIDInitializedStr):
+		return BlockchainIDInitialized, nil
 	default:
 		return Unknown, fmt.Errorf("unknown event %s", e)
 	}
@@ -83,27 +92,107 @@ func FilterTeleporterEvents(topics []common.Hash, data []byte, event string) (in
 	if err != nil {
 		return nil, err
 	}
-	var out interface{}
+	out, err := newTeleporterEvent(e)
+	if err != nil {
+		return nil, err
+	}
+	if err := UnpackEvent(out, e.String(), topics, data); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ErrUnknownEvent is returned by DecodeLog for logs that are not TeleporterMessenger events
+var ErrUnknownEvent = errors.New("unknown Teleporter event")
+
+// TeleporterEvent is a decoded TeleporterMessenger event, returned by DecodeLog. It is implemented by
+// the TeleporterMessenger* event structs, and cannot be implemented outside of this package.
+type TeleporterEvent interface {
+	// Kind returns the type of the event
+	Kind() Event
+	// GetMessageID returns the ID of the message the event is for, or the empty ID for events that are
+	// not for a single message. It is not named MessageID, since that is the name of the event field.
+	GetMessageID() ids.ID
+
+	setRaw(log types.Log)
+}
+
+// DecodeLog decodes a log emitted by the TeleporterMessenger contract into the corresponding event struct,
+// identifying the event by the signature hash in the log's first topic. The log is set as the event's Raw field.
+func DecodeLog(log types.Log) (TeleporterEvent, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("%w: log has no topics", ErrUnknownEvent)
+	}
+	teleporterABI, err := TeleporterMessengerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get abi: %v", err)
+	}
+	abiEvent, err := teleporterABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, log.Topics[0])
+	}
+	e, err := ToEvent(abiEvent.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, abiEvent.Name)
+	}
+	out, err := newTeleporterEvent(e)
+	if err != nil {
+		return nil, err
+	}
+	if err := UnpackEvent(out, e.String(), log.Topics, log.Data); err != nil {
+		return nil, fmt.Errorf("failed to unpack %s event: %w", e, err)
+	}
+	out.setRaw(log)
+	return out, nil
+}
+
+// newTeleporterEvent returns an empty event struct of the event type
+func newTeleporterEvent(e Event) (TeleporterEvent, error) {
 	switch e {
 	case SendCrossChainMessage:
-		out = new(TeleporterMessengerSendCrossChainMessage)
+		return new(TeleporterMessengerSendCrossChainMessage), nil
 	case ReceiveCrossChainMessage:
-		out = new(TeleporterMessengerReceiveCrossChainMessage)
+		return new(TeleporterMessengerReceiveCrossChainMessage), nil
 	case AddFeeAmount:
-		out = new(TeleporterMessengerAddFeeAmount)
+		return new(TeleporterMessengerAddFeeAmount), nil
 	case MessageExecutionFailed:
-		out = new(TeleporterMessengerMessageExecutionFailed)
+		return new(TeleporterMessengerMessageExecutionFailed), nil
 	case MessageExecuted:
-		out = new(TeleporterMessengerMessageExecuted)
+		return new(TeleporterMessengerMessageExecuted), nil
 	case RelayerRewardsRedeemed:
-		out = new(TeleporterMessengerRelayerRewardsRedeemed)
+		return new(TeleporterMessengerRelayerRewardsRedeemed), nil
 	case ReceiptReceived:
-		out = new(TeleporterMessengerReceiptReceived)
+		return new(TeleporterMessengerReceiptReceived), nil
+	case BlockchainIDInitialized:
+		return new(TeleporterMessengerBlockchainIDInitialized), nil
 	default:
 		return nil, fmt.Errorf("unknown event %s", e.String())
 	}
-	if err := UnpackEvent(out, e.String(), topics, data); err != nil {
-		return nil, err
-	}
-	return out, nil
 }
+
+func (*TeleporterMessengerSendCrossChainMessage) Kind() Event    { return SendCrossChainMessage }
+func (*TeleporterMessengerReceiveCrossChainMessage) Kind() Event { return ReceiveCrossChainMessage }
+func (*TeleporterMessengerAddFeeAmount) Kind() Event             { return AddFeeAmount }
+func (*TeleporterMessengerMessageExecutionFailed) Kind() Event   { return MessageExecutionFailed }
+func (*TeleporterMessengerMessageExecuted) Kind() Event          { return MessageExecuted }
+func (*TeleporterMessengerRelayerRewardsRedeemed) Kind() Event   { return RelayerRewardsRedeemed }
+func (*TeleporterMessengerReceiptReceived) Kind() Event          { return ReceiptReceived }
+func (*TeleporterMessengerBlockchainIDInitialized) Kind() Event  { return BlockchainIDInitialized }
+
+func (e *TeleporterMessengerSendCrossChainMessage) GetMessageID() ids.ID    { return e.MessageID }
+func (e *TeleporterMessengerReceiveCrossChainMessage) GetMessageID() ids.ID { return e.MessageID }
+func (e *TeleporterMessengerAddFeeAmount) GetMessageID() ids.ID             { return e.MessageID }
+func (e *TeleporterMessengerMessageExecutionFailed) GetMessageID() ids.ID   { return e.MessageID }
+func (e *TeleporterMessengerMessageExecuted) GetMessageID() ids.ID          { return e.MessageID }
+func (*TeleporterMessengerRelayerRewardsRedeemed) GetMessageID() ids.ID     { return ids.Empty }
+func (e *TeleporterMessengerReceiptReceived) GetMessageID() ids.ID          { return e.MessageID }
+func (*TeleporterMessengerBlockchainIDInitialized) GetMessageID() ids.ID    { return ids.Empty }
+
+func (e *TeleporterMessengerSendCrossChainMessage) setRaw(log types.Log)    { e.Raw = log }
+func (e *TeleporterMessengerReceiveCrossChainMessage) setRaw(log types.Log) { e.Raw = log }
+func (e *TeleporterMessengerAddFeeAmount) setRaw(log types.Log)             { e.Raw = log }
+func (e *TeleporterMessengerMessageExecutionFailed) setRaw(log types.Log)   { e.Raw = log }
+func (e *TeleporterMessengerMessageExecuted) setRaw(log types.Log)          { e.Raw = log }
+func (e *TeleporterMessengerRelayerRewardsRedeemed) setRaw(log types.Log)   { e.Raw = log }
+func (e *TeleporterMessengerReceiptReceived) setRaw(log types.Log)          { e.Raw = log }
+func (e *TeleporterMessengerBlockchainIDInitialized) setRaw(log types.Log)  { e.Raw = log }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +26,8 @@ func TestEventString(t *testing.T) {
 			{MessageExecutionFailed, messageExecutionFailedStr},
 			{MessageExecuted, messageExecutedStr},
 			{RelayerRewardsRedeemed, relayerRewardsRedeemedStr},
+			{ReceiptReceived, receiptReceivedStr},
+			{BlockchainIDInitialized, blockchainIDInitializedStr},
 		}
 	)
 
@@ -49,6 +52,8 @@ func TestToEvent(t *testing.T) {
 			{messageExecutionFailedStr, MessageExecutionFailed, false},
 			{messageExecutedStr, MessageExecuted, false},
 			{relayerRewardsRedeemedStr, RelayerRewardsRedeemed, false},
+			{receiptReceivedStr, ReceiptReceived, false},
+			{blockchainIDInitializedStr, BlockchainIDInitialized, false},
 		}
 	)
 
@@ -143,3 +148,130 @@ func TestFilterTeleporterEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeLog(t *testing.T) {
+	mockBlockchainID := ids.ID{1, 2, 3, 4}
+	mockMessageID := ids.ID{9, 10, 11, 12}
+	message := createTestTeleporterMessage(big.NewInt(8))
+	feeInfo := TeleporterFeeInfo{
+		FeeTokenAddress: common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		Amount:          big.NewInt(1),
+	}
+	address := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+
+	teleporterABI, err := TeleporterMessengerMetaData.GetAbi()
+	require.NoError(t, err)
+
+	var (
+		tests = []struct {
+			event     Event
+			args      []interface{}
+			messageID ids.ID
+			expected  TeleporterEvent
+		}{
+			{
+				event:     SendCrossChainMessage,
+				args:      []interface{}{mockMessageID, mockBlockchainID, message, feeInfo},
+				messageID: mockMessageID,
+				expected: &TeleporterMessengerSendCrossChainMessage{
+					MessageID:               mockMessageID,
+					DestinationBlockchainID: mockBlockchainID,
+					Message:                 message,
+					FeeInfo:                 feeInfo,
+				},
+			},
+			{
+				event:     ReceiveCrossChainMessage,
+				args:      []interface{}{mockMessageID, mockBlockchainID, address, address, message},
+				messageID: mockMessageID,
+				expected: &TeleporterMessengerReceiveCrossChainMessage{
+					MessageID:          mockMessageID,
+					SourceBlockchainID: mockBlockchainID,
+					Deliverer:          address,
+					RewardRedeemer:     address,
+					Message:            message,
+				},
+			},
+			{
+				event:     AddFeeAmount,
+				args:      []interface{}{mockMessageID, feeInfo},
+				messageID: mockMessageID,
+				expected: &TeleporterMessengerAddFeeAmount{
+					MessageID:      mockMessageID,
+					UpdatedFeeInfo: feeInfo,
+				},
+			},
+			{
+				event:     MessageExecutionFailed,
+				args:      []interface{}{mockMessageID, mockBlockchainID, message},
+				messageID: mockMessageID,
+				expected: &TeleporterMessengerMessageExecutionFailed{
+					MessageID:          mockMessageID,
+					SourceBlockchainID: mockBlockchainID,
+					Message:            message,
+				},
+			},
+			{
+				event:     MessageExecuted,
+				args:      []interface{}{mockMessageID, mockBlockchainID},
+				messageID: mockMessageID,
+				expected: &TeleporterMessengerMessageExecuted{
+					MessageID:          mockMessageID,
+					SourceBlockchainID: mockBlockchainID,
+				},
+			},
+			{
+				event:     RelayerRewardsRedeemed,
+				args:      []interface{}{address, address, big.NewInt(5)},
+				messageID: ids.Empty,
+				expected: &TeleporterMessengerRelayerRewardsRedeemed{
+					Redeemer: address,
+					Asset:    address,
+					Amount:   big.NewInt(5),
+				},
+			},
+			{
+				event:     ReceiptReceived,
+				args:      []interface{}{mockMessageID, mockBlockchainID, address, feeInfo},
+				messageID: mockMessageID,
+				expected: &TeleporterMessengerReceiptReceived{
+					MessageID:               mockMessageID,
+					DestinationBlockchainID: mockBlockchainID,
+					RelayerRewardAddress:    address,
+					FeeInfo:                 feeInfo,
+				},
+			},
+			{
+				event:     BlockchainIDInitialized,
+				args:      []interface{}{mockBlockchainID},
+				messageID: ids.Empty,
+				expected: &TeleporterMessengerBlockchainIDInitialized{
+					BlockchainID: mockBlockchainID,
+				},
+			},
+		}
+	)
+
+	for _, test := range tests {
+		t.Run(test.event.String(), func(t *testing.T) {
+			topics, data, err := teleporterABI.PackEvent(test.event.String(), test.args...)
+			require.NoError(t, err)
+			log := types.Log{Address: address, Topics: topics, Data: data, BlockNumber: 7}
+
+			out, err := DecodeLog(log)
+			require.NoError(t, err)
+			require.Equal(t, test.event, out.Kind())
+			require.Equal(t, test.messageID, out.GetMessageID())
+
+			test.expected.setRaw(log)
+			require.Equal(t, test.expected, out)
+		})
+	}
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := DecodeLog(types.Log{Topics: []common.Hash{common.HexToHash("0x1234")}})
+		require.ErrorIs(t, err, ErrUnknownEvent)
+		_, err = DecodeLog(types.Log{})
+		require.ErrorIs(t, err, ErrUnknownEvent)
+	})
+}
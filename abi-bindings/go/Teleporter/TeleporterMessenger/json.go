@@ -0,0 +1,592 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package teleportermessenger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The JSON encodings below are shared with services in other languages. Bytes and addresses are 0x prefixed
+// hex encoded, blockchain and message IDs are encoded both as CB58 and as hex in a sibling field suffixed by
+// Hex, and big integers are decimal strings, so that they are not rounded when parsed as JavaScript numbers.
+// When decoding, either encoding of an ID is accepted, and if both are provided they must match.
+
+// decimalBigInt encodes a big integer as a JSON decimal string. Nil integers are encoded as null.
+type decimalBigInt big.Int
+
+func (b *decimalBigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*big.Int)(b).String())
+}
+
+func (b *decimalBigInt) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("big integer must be a decimal string: %w", err)
+	}
+	if _, ok := (*big.Int)(b).SetString(s, 10); !ok {
+		return fmt.Errorf("invalid decimal integer %q", s)
+	}
+	return nil
+}
+
+// encodeID returns the CB58 and hex encodings of a blockchain or message ID
+func encodeID(id [32]byte) (string, string) {
+	return ids.ID(id).String(), hexutil.Encode(id[:])
+}
+
+// decodeID decodes the named blockchain or message ID from its CB58 or hex encoding, or both
+func decodeID(name string, cb58 string, hex string) ([32]byte, error) {
+	if cb58 == "" && hex == "" {
+		return [32]byte{}, fmt.Errorf("missing %s", name)
+	}
+	var fromCB58, fromHex ids.ID
+	if cb58 != "" {
+		id, err := ids.FromString(cb58)
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("invalid CB58 encoded %s: %w", name, err)
+		}
+		fromCB58 = id
+	}
+	if hex != "" {
+		if !strings.HasPrefix(hex, "0x") {
+			return [32]byte{}, fmt.Errorf("invalid hex encoded %s %s: missing 0x prefix", name, hex)
+		}
+		b, err := hexutil.Decode(hex)
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("invalid hex encoded %s: %w", name, err)
+		}
+		id, err := ids.ToID(b)
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("invalid hex encoded %s: %w", name, err)
+		}
+		fromHex = id
+	}
+	switch {
+	case cb58 == "":
+		return fromHex, nil
+	case hex == "":
+		return fromCB58, nil
+	case fromCB58 != fromHex:
+		return [32]byte{}, fmt.Errorf("CB58 encoded %s %s does not match hex encoded %s %s", name, cb58, name, hex)
+	default:
+		return fromCB58, nil
+	}
+}
+
+type feeInfoJSON struct {
+	FeeTokenAddress common.Address `json:"feeTokenAddress"`
+	Amount          *decimalBigInt `json:"amount"`
+}
+
+func (f TeleporterFeeInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(feeInfoJSON{
+		FeeTokenAddress: f.FeeTokenAddress,
+		Amount:          (*decimalBigInt)(f.Amount),
+	})
+}
+
+func (f *TeleporterFeeInfo) UnmarshalJSON(data []byte) error {
+	var dec feeInfoJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	f.FeeTokenAddress = dec.FeeTokenAddress
+	f.Amount = (*big.Int)(dec.Amount)
+	return nil
+}
+
+type messageReceiptJSON struct {
+	ReceivedMessageNonce *decimalBigInt `json:"receivedMessageNonce"`
+	RelayerRewardAddress common.Address `json:"relayerRewardAddress"`
+}
+
+func (r TeleporterMessageReceipt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(messageReceiptJSON{
+		ReceivedMessageNonce: (*decimalBigInt)(r.ReceivedMessageNonce),
+		RelayerRewardAddress: r.RelayerRewardAddress,
+	})
+}
+
+func (r *TeleporterMessageReceipt) UnmarshalJSON(data []byte) error {
+	var dec messageReceiptJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	r.ReceivedMessageNonce = (*big.Int)(dec.ReceivedMessageNonce)
+	r.RelayerRewardAddress = dec.RelayerRewardAddress
+	return nil
+}
+
+type messageJSON struct {
+	MessageNonce               *decimalBigInt             `json:"messageNonce"`
+	OriginSenderAddress        common.Address             `json:"originSenderAddress"`
+	DestinationBlockchainID    string                     `json:"destinationBlockchainID"`
+	DestinationBlockchainIDHex string                     `json:"destinationBlockchainIDHex"`
+	DestinationAddress         common.Address             `json:"destinationAddress"`
+	RequiredGasLimit           *decimalBigInt             `json:"requiredGasLimit"`
+	AllowedRelayerAddresses    []common.Address           `json:"allowedRelayerAddresses"`
+	Receipts                   []TeleporterMessageReceipt `json:"receipts"`
+	Message                    hexutil.Bytes              `json:"message"`
+}
+
+func (m TeleporterMessage) MarshalJSON() ([]byte, error) {
+	enc := messageJSON{
+		MessageNonce:            (*decimalBigInt)(m.MessageNonce),
+		OriginSenderAddress:     m.OriginSenderAddress,
+		DestinationAddress:      m.DestinationAddress,
+		RequiredGasLimit:        (*decimalBigInt)(m.RequiredGasLimit),
+		AllowedRelayerAddresses: m.AllowedRelayerAddresses,
+		Receipts:                m.Receipts,
+		Message:                 m.Message,
+	}
+	enc.DestinationBlockchainID, enc.DestinationBlockchainIDHex = encodeID(m.DestinationBlockchainID)
+	return json.Marshal(enc)
+}
+
+func (m *TeleporterMessage) UnmarshalJSON(data []byte) error {
+	var dec messageJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	destinationBlockchainID, err := decodeID(
+		"destinationBlockchainID", dec.DestinationBlockchainID, dec.DestinationBlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*m = TeleporterMessage{
+		MessageNonce:            (*big.Int)(dec.MessageNonce),
+		OriginSenderAddress:     dec.OriginSenderAddress,
+		DestinationBlockchainID: destinationBlockchainID,
+		DestinationAddress:      dec.DestinationAddress,
+		RequiredGasLimit:        (*big.Int)(dec.RequiredGasLimit),
+		AllowedRelayerAddresses: dec.AllowedRelayerAddresses,
+		Receipts:                dec.Receipts,
+		Message:                 dec.Message,
+	}
+	return nil
+}
+
+type messageInputJSON struct {
+	DestinationBlockchainID    string            `json:"destinationBlockchainID"`
+	DestinationBlockchainIDHex string            `json:"destinationBlockchainIDHex"`
+	DestinationAddress         common.Address    `json:"destinationAddress"`
+	FeeInfo                    TeleporterFeeInfo `json:"feeInfo"`
+	RequiredGasLimit           *decimalBigInt    `json:"requiredGasLimit"`
+	AllowedRelayerAddresses    []common.Address  `json:"allowedRelayerAddresses"`
+	Message                    hexutil.Bytes     `json:"message"`
+}
+
+func (m TeleporterMessageInput) MarshalJSON() ([]byte, error) {
+	enc := messageInputJSON{
+		DestinationAddress:      m.DestinationAddress,
+		FeeInfo:                 m.FeeInfo,
+		RequiredGasLimit:        (*decimalBigInt)(m.RequiredGasLimit),
+		AllowedRelayerAddresses: m.AllowedRelayerAddresses,
+		Message:                 m.Message,
+	}
+	enc.DestinationBlockchainID, enc.DestinationBlockchainIDHex = encodeID(m.DestinationBlockchainID)
+	return json.Marshal(enc)
+}
+
+func (m *TeleporterMessageInput) UnmarshalJSON(data []byte) error {
+	var dec messageInputJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	destinationBlockchainID, err := decodeID(
+		"destinationBlockchainID", dec.DestinationBlockchainID, dec.DestinationBlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*m = TeleporterMessageInput{
+		DestinationBlockchainID: destinationBlockchainID,
+		DestinationAddress:      dec.DestinationAddress,
+		FeeInfo:                 dec.FeeInfo,
+		RequiredGasLimit:        (*big.Int)(dec.RequiredGasLimit),
+		AllowedRelayerAddresses: dec.AllowedRelayerAddresses,
+		Message:                 dec.Message,
+	}
+	return nil
+}
+
+// eventJSON holds the fields common to the JSON encodings of all events: the name of the event, and
+// the log it was decoded from, which is omitted for events that were not decoded from a log.
+type eventJSON struct {
+	Event string     `json:"event"`
+	Raw   *types.Log `json:"raw,omitempty"`
+}
+
+func newEventJSON(kind Event, raw types.Log) eventJSON {
+	enc := eventJSON{Event: kind.String()}
+	if raw.Address != (common.Address{}) || len(raw.Topics) > 0 || raw.TxHash != (common.Hash{}) {
+		enc.Raw = &raw
+	}
+	return enc
+}
+
+// raw checks that the decoded event is of the given kind, if its name is set, and returns its log
+func (e eventJSON) raw(kind Event) (types.Log, error) {
+	if e.Event != "" && e.Event != kind.String() {
+		return types.Log{}, fmt.Errorf("cannot decode %s event as %s", e.Event, kind)
+	}
+	if e.Raw == nil {
+		return types.Log{}, nil
+	}
+	return *e.Raw, nil
+}
+
+type messageIDJSON struct {
+	MessageID    string `json:"messageID"`
+	MessageIDHex string `json:"messageIDHex"`
+}
+
+func newMessageIDJSON(messageID [32]byte) messageIDJSON {
+	var enc messageIDJSON
+	enc.MessageID, enc.MessageIDHex = encodeID(messageID)
+	return enc
+}
+
+func (m messageIDJSON) decode() ([32]byte, error) {
+	return decodeID("messageID", m.MessageID, m.MessageIDHex)
+}
+
+type sendCrossChainMessageJSON struct {
+	eventJSON
+	messageIDJSON
+	DestinationBlockchainID    string            `json:"destinationBlockchainID"`
+	DestinationBlockchainIDHex string            `json:"destinationBlockchainIDHex"`
+	Message                    TeleporterMessage `json:"message"`
+	FeeInfo                    TeleporterFeeInfo `json:"feeInfo"`
+}
+
+func (e TeleporterMessengerSendCrossChainMessage) MarshalJSON() ([]byte, error) {
+	enc := sendCrossChainMessageJSON{
+		eventJSON:     newEventJSON(SendCrossChainMessage, e.Raw),
+		messageIDJSON: newMessageIDJSON(e.MessageID),
+		Message:       e.Message,
+		FeeInfo:       e.FeeInfo,
+	}
+	enc.DestinationBlockchainID, enc.DestinationBlockchainIDHex = encodeID(e.DestinationBlockchainID)
+	return json.Marshal(enc)
+}
+
+func (e *TeleporterMessengerSendCrossChainMessage) UnmarshalJSON(data []byte) error {
+	var dec sendCrossChainMessageJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(SendCrossChainMessage)
+	if err != nil {
+		return err
+	}
+	messageID, err := dec.messageIDJSON.decode()
+	if err != nil {
+		return err
+	}
+	destinationBlockchainID, err := decodeID(
+		"destinationBlockchainID", dec.DestinationBlockchainID, dec.DestinationBlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerSendCrossChainMessage{
+		MessageID:               messageID,
+		DestinationBlockchainID: destinationBlockchainID,
+		Message:                 dec.Message,
+		FeeInfo:                 dec.FeeInfo,
+		Raw:                     raw,
+	}
+	return nil
+}
+
+type receiveCrossChainMessageJSON struct {
+	eventJSON
+	messageIDJSON
+	SourceBlockchainID    string            `json:"sourceBlockchainID"`
+	SourceBlockchainIDHex string            `json:"sourceBlockchainIDHex"`
+	Deliverer             common.Address    `json:"deliverer"`
+	RewardRedeemer        common.Address    `json:"rewardRedeemer"`
+	Message               TeleporterMessage `json:"message"`
+}
+
+func (e TeleporterMessengerReceiveCrossChainMessage) MarshalJSON() ([]byte, error) {
+	enc := receiveCrossChainMessageJSON{
+		eventJSON:      newEventJSON(ReceiveCrossChainMessage, e.Raw),
+		messageIDJSON:  newMessageIDJSON(e.MessageID),
+		Deliverer:      e.Deliverer,
+		RewardRedeemer: e.RewardRedeemer,
+		Message:        e.Message,
+	}
+	enc.SourceBlockchainID, enc.SourceBlockchainIDHex = encodeID(e.SourceBlockchainID)
+	return json.Marshal(enc)
+}
+
+func (e *TeleporterMessengerReceiveCrossChainMessage) UnmarshalJSON(data []byte) error {
+	var dec receiveCrossChainMessageJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(ReceiveCrossChainMessage)
+	if err != nil {
+		return err
+	}
+	messageID, err := dec.messageIDJSON.decode()
+	if err != nil {
+		return err
+	}
+	sourceBlockchainID, err := decodeID("sourceBlockchainID", dec.SourceBlockchainID, dec.SourceBlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerReceiveCrossChainMessage{
+		MessageID:          messageID,
+		SourceBlockchainID: sourceBlockchainID,
+		Deliverer:          dec.Deliverer,
+		RewardRedeemer:     dec.RewardRedeemer,
+		Message:            dec.Message,
+		Raw:                raw,
+	}
+	return nil
+}
+
+type addFeeAmountJSON struct {
+	eventJSON
+	messageIDJSON
+	UpdatedFeeInfo TeleporterFeeInfo `json:"updatedFeeInfo"`
+}
+
+func (e TeleporterMessengerAddFeeAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(addFeeAmountJSON{
+		eventJSON:      newEventJSON(AddFeeAmount, e.Raw),
+		messageIDJSON:  newMessageIDJSON(e.MessageID),
+		UpdatedFeeInfo: e.UpdatedFeeInfo,
+	})
+}
+
+func (e *TeleporterMessengerAddFeeAmount) UnmarshalJSON(data []byte) error {
+	var dec addFeeAmountJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(AddFeeAmount)
+	if err != nil {
+		return err
+	}
+	messageID, err := dec.messageIDJSON.decode()
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerAddFeeAmount{
+		MessageID:      messageID,
+		UpdatedFeeInfo: dec.UpdatedFeeInfo,
+		Raw:            raw,
+	}
+	return nil
+}
+
+type messageExecutionFailedJSON struct {
+	eventJSON
+	messageIDJSON
+	SourceBlockchainID    string            `json:"sourceBlockchainID"`
+	SourceBlockchainIDHex string            `json:"sourceBlockchainIDHex"`
+	Message               TeleporterMessage `json:"message"`
+}
+
+func (e TeleporterMessengerMessageExecutionFailed) MarshalJSON() ([]byte, error) {
+	enc := messageExecutionFailedJSON{
+		eventJSON:     newEventJSON(MessageExecutionFailed, e.Raw),
+		messageIDJSON: newMessageIDJSON(e.MessageID),
+		Message:       e.Message,
+	}
+	enc.SourceBlockchainID, enc.SourceBlockchainIDHex = encodeID(e.SourceBlockchainID)
+	return json.Marshal(enc)
+}
+
+func (e *TeleporterMessengerMessageExecutionFailed) UnmarshalJSON(data []byte) error {
+	var dec messageExecutionFailedJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(MessageExecutionFailed)
+	if err != nil {
+		return err
+	}
+	messageID, err := dec.messageIDJSON.decode()
+	if err != nil {
+		return err
+	}
+	sourceBlockchainID, err := decodeID("sourceBlockchainID", dec.SourceBlockchainID, dec.SourceBlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerMessageExecutionFailed{
+		MessageID:          messageID,
+		SourceBlockchainID: sourceBlockchainID,
+		Message:            dec.Message,
+		Raw:                raw,
+	}
+	return nil
+}
+
+type messageExecutedJSON struct {
+	eventJSON
+	messageIDJSON
+	SourceBlockchainID    string `json:"sourceBlockchainID"`
+	SourceBlockchainIDHex string `json:"sourceBlockchainIDHex"`
+}
+
+func (e TeleporterMessengerMessageExecuted) MarshalJSON() ([]byte, error) {
+	enc := messageExecutedJSON{
+		eventJSON:     newEventJSON(MessageExecuted, e.Raw),
+		messageIDJSON: newMessageIDJSON(e.MessageID),
+	}
+	enc.SourceBlockchainID, enc.SourceBlockchainIDHex = encodeID(e.SourceBlockchainID)
+	return json.Marshal(enc)
+}
+
+func (e *TeleporterMessengerMessageExecuted) UnmarshalJSON(data []byte) error {
+	var dec messageExecutedJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(MessageExecuted)
+	if err != nil {
+		return err
+	}
+	messageID, err := dec.messageIDJSON.decode()
+	if err != nil {
+		return err
+	}
+	sourceBlockchainID, err := decodeID("sourceBlockchainID", dec.SourceBlockchainID, dec.SourceBlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerMessageExecuted{
+		MessageID:          messageID,
+		SourceBlockchainID: sourceBlockchainID,
+		Raw:                raw,
+	}
+	return nil
+}
+
+type relayerRewardsRedeemedJSON struct {
+	eventJSON
+	Redeemer common.Address `json:"redeemer"`
+	Asset    common.Address `json:"asset"`
+	Amount   *decimalBigInt `json:"amount"`
+}
+
+func (e TeleporterMessengerRelayerRewardsRedeemed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(relayerRewardsRedeemedJSON{
+		eventJSON: newEventJSON(RelayerRewardsRedeemed, e.Raw),
+		Redeemer:  e.Redeemer,
+		Asset:     e.Asset,
+		Amount:    (*decimalBigInt)(e.Amount),
+	})
+}
+
+func (e *TeleporterMessengerRelayerRewardsRedeemed) UnmarshalJSON(data []byte) error {
+	var dec relayerRewardsRedeemedJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(RelayerRewardsRedeemed)
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerRelayerRewardsRedeemed{
+		Redeemer: dec.Redeemer,
+		Asset:    dec.Asset,
+		Amount:   (*big.Int)(dec.Amount),
+		Raw:      raw,
+	}
+	return nil
+}
+
+type receiptReceivedJSON struct {
+	eventJSON
+	messageIDJSON
+	DestinationBlockchainID    string            `json:"destinationBlockchainID"`
+	DestinationBlockchainIDHex string            `json:"destinationBlockchainIDHex"`
+	RelayerRewardAddress       common.Address    `json:"relayerRewardAddress"`
+	FeeInfo                    TeleporterFeeInfo `json:"feeInfo"`
+}
+
+func (e TeleporterMessengerReceiptReceived) MarshalJSON() ([]byte, error) {
+	enc := receiptReceivedJSON{
+		eventJSON:            newEventJSON(ReceiptReceived, e.Raw),
+		messageIDJSON:        newMessageIDJSON(e.MessageID),
+		RelayerRewardAddress: e.RelayerRewardAddress,
+		FeeInfo:              e.FeeInfo,
+	}
+	enc.DestinationBlockchainID, enc.DestinationBlockchainIDHex = encodeID(e.DestinationBlockchainID)
+	return json.Marshal(enc)
+}
+
+func (e *TeleporterMessengerReceiptReceived) UnmarshalJSON(data []byte) error {
+	var dec receiptReceivedJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(ReceiptReceived)
+	if err != nil {
+		return err
+	}
+	messageID, err := dec.messageIDJSON.decode()
+	if err != nil {
+		return err
+	}
+	destinationBlockchainID, err := decodeID(
+		"destinationBlockchainID", dec.DestinationBlockchainID, dec.DestinationBlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerReceiptReceived{
+		MessageID:               messageID,
+		DestinationBlockchainID: destinationBlockchainID,
+		RelayerRewardAddress:    dec.RelayerRewardAddress,
+		FeeInfo:                 dec.FeeInfo,
+		Raw:                     raw,
+	}
+	return nil
+}
+
+type blockchainIDInitializedJSON struct {
+	eventJSON
+	BlockchainID    string `json:"blockchainID"`
+	BlockchainIDHex string `json:"blockchainIDHex"`
+}
+
+func (e TeleporterMessengerBlockchainIDInitialized) MarshalJSON() ([]byte, error) {
+	enc := blockchainIDInitializedJSON{eventJSON: newEventJSON(BlockchainIDInitialized, e.Raw)}
+	enc.BlockchainID, enc.BlockchainIDHex = encodeID(e.BlockchainID)
+	return json.Marshal(enc)
+}
+
+func (e *TeleporterMessengerBlockchainIDInitialized) UnmarshalJSON(data []byte) error {
+	var dec blockchainIDInitializedJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	raw, err := dec.raw(BlockchainIDInitialized)
+	if err != nil {
+		return err
+	}
+	blockchainID, err := decodeID("blockchainID", dec.BlockchainID, dec.BlockchainIDHex)
+	if err != nil {
+		return err
+	}
+	*e = TeleporterMessengerBlockchainIDInitialized{
+		BlockchainID: blockchainID,
+		Raw:          raw,
+	}
+	return nil
+}
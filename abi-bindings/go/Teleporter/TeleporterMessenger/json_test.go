@@ -0,0 +1,269 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package teleportermessenger
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalTeleporterMessageJSON(t *testing.T) {
+	message := createTestTeleporterMessage(big.NewInt(4))
+
+	b, err := json.Marshal(message)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"messageNonce": "4",
+		"originSenderAddress": "0x0123456789abcdef0123456789abcdef01234567",
+		"destinationBlockchainID": "`+ids.ID{1, 2, 3, 4}.String()+`",
+		"destinationBlockchainIDHex": "0x0102030400000000000000000000000000000000000000000000000000000000",
+		"destinationAddress": "0x0123456789abcdef0123456789abcdef01234567",
+		"requiredGasLimit": "2",
+		"allowedRelayerAddresses": ["0x0123456789abcdef0123456789abcdef01234567"],
+		"receipts": [{
+			"receivedMessageNonce": "1",
+			"relayerRewardAddress": "0x0123456789abcdef0123456789abcdef01234567"
+		}],
+		"message": "0x01020304"
+	}`, string(b))
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	// Larger than 2^53, which JSON numbers cannot represent exactly in many languages
+	amount, ok := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require.True(t, ok)
+	message := createTestTeleporterMessage(amount)
+	feeInfo := TeleporterFeeInfo{
+		FeeTokenAddress: common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		Amount:          amount,
+	}
+	messageID := ids.GenerateTestID()
+	blockchainID := ids.GenerateTestID()
+	raw := types.Log{
+		Address:     common.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf"),
+		Topics:      []common.Hash{common.HexToHash("0x1"), common.Hash(messageID)},
+		Data:        []byte{1, 2, 3},
+		BlockNumber: 10,
+		TxHash:      common.HexToHash("0x2"),
+		TxIndex:     1,
+		BlockHash:   common.HexToHash("0x3"),
+		Index:       2,
+	}
+
+	var tests = []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name:  "fee info",
+			value: &feeInfo,
+		},
+		{
+			name:  "fee info without amount",
+			value: &TeleporterFeeInfo{},
+		},
+		{
+			name:  "message",
+			value: &message,
+		},
+		{
+			name: "message input",
+			value: &TeleporterMessageInput{
+				DestinationBlockchainID: blockchainID,
+				DestinationAddress:      common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+				FeeInfo:                 feeInfo,
+				RequiredGasLimit:        big.NewInt(100_000),
+				AllowedRelayerAddresses: []common.Address{},
+				Message:                 []byte{1, 2, 3, 4},
+			},
+		},
+		{
+			name: "SendCrossChainMessage",
+			value: &TeleporterMessengerSendCrossChainMessage{
+				MessageID:               messageID,
+				DestinationBlockchainID: blockchainID,
+				Message:                 message,
+				FeeInfo:                 feeInfo,
+				Raw:                     raw,
+			},
+		},
+		{
+			name: "ReceiveCrossChainMessage",
+			value: &TeleporterMessengerReceiveCrossChainMessage{
+				MessageID:          messageID,
+				SourceBlockchainID: blockchainID,
+				Deliverer:          common.HexToAddress("0x4"),
+				RewardRedeemer:     common.HexToAddress("0x5"),
+				Message:            message,
+				Raw:                raw,
+			},
+		},
+		{
+			name: "AddFeeAmount",
+			value: &TeleporterMessengerAddFeeAmount{
+				MessageID:      messageID,
+				UpdatedFeeInfo: feeInfo,
+				Raw:            raw,
+			},
+		},
+		{
+			name: "MessageExecutionFailed",
+			value: &TeleporterMessengerMessageExecutionFailed{
+				MessageID:          messageID,
+				SourceBlockchainID: blockchainID,
+				Message:            message,
+				Raw:                raw,
+			},
+		},
+		{
+			name: "MessageExecuted",
+			value: &TeleporterMessengerMessageExecuted{
+				MessageID:          messageID,
+				SourceBlockchainID: blockchainID,
+				Raw:                raw,
+			},
+		},
+		{
+			name: "RelayerRewardsRedeemed",
+			value: &TeleporterMessengerRelayerRewardsRedeemed{
+				Redeemer: common.HexToAddress("0x4"),
+				Asset:    common.HexToAddress("0x5"),
+				Amount:   amount,
+				Raw:      raw,
+			},
+		},
+		{
+			name: "ReceiptReceived",
+			value: &TeleporterMessengerReceiptReceived{
+				MessageID:               messageID,
+				DestinationBlockchainID: blockchainID,
+				RelayerRewardAddress:    common.HexToAddress("0x4"),
+				FeeInfo:                 feeInfo,
+				Raw:                     raw,
+			},
+		},
+		{
+			name: "BlockchainIDInitialized",
+			value: &TeleporterMessengerBlockchainIDInitialized{
+				BlockchainID: blockchainID,
+				Raw:          raw,
+			},
+		},
+		{
+			name: "event without log",
+			value: &TeleporterMessengerMessageExecuted{
+				MessageID:          messageID,
+				SourceBlockchainID: blockchainID,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.value)
+			require.NoError(t, err)
+
+			decoded := reflect.New(reflect.TypeOf(tt.value).Elem()).Interface()
+			require.NoError(t, json.Unmarshal(b, decoded))
+			require.Equal(t, tt.value, decoded)
+		})
+	}
+}
+
+func TestMarshalEventJSON(t *testing.T) {
+	messageID := ids.GenerateTestID()
+	event := TeleporterMessengerMessageExecuted{MessageID: messageID}
+
+	b, err := json.Marshal(event)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &fields))
+	require.Equal(t, messageExecutedStr, fields["event"])
+	require.Equal(t, messageID.String(), fields["messageID"])
+	require.Equal(t, "0x"+messageID.Hex(), fields["messageIDHex"])
+	require.NotContains(t, fields, "raw")
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	messageID := ids.GenerateTestID()
+	otherID := ids.GenerateTestID()
+
+	var tests = []struct {
+		name  string
+		json  string
+		value interface{}
+		err   string
+	}{
+		{
+			name:  "CB58 ID only",
+			json:  `{"messageID": "` + messageID.String() + `"}`,
+			value: &TeleporterMessengerAddFeeAmount{},
+		},
+		{
+			name:  "hex ID only",
+			json:  `{"messageIDHex": "0x` + messageID.Hex() + `"}`,
+			value: &TeleporterMessengerAddFeeAmount{},
+		},
+		{
+			name:  "mismatched IDs",
+			json:  `{"messageID": "` + messageID.String() + `", "messageIDHex": "0x` + otherID.Hex() + `"}`,
+			value: &TeleporterMessengerAddFeeAmount{},
+			err:   "does not match hex encoded messageID",
+		},
+		{
+			name:  "missing ID",
+			json:  `{}`,
+			value: &TeleporterMessengerAddFeeAmount{},
+			err:   "missing messageID",
+		},
+		{
+			name:  "hex ID without prefix",
+			json:  `{"messageIDHex": "` + messageID.Hex() + `"}`,
+			value: &TeleporterMessengerAddFeeAmount{},
+			err:   "missing 0x prefix",
+		},
+		{
+			name:  "short hex ID",
+			json:  `{"messageIDHex": "0x0102"}`,
+			value: &TeleporterMessengerAddFeeAmount{},
+			err:   "invalid hex encoded messageID",
+		},
+		{
+			name:  "wrong event",
+			json:  `{"event": "` + messageExecutedStr + `", "messageID": "` + messageID.String() + `"}`,
+			value: &TeleporterMessengerAddFeeAmount{},
+			err:   "cannot decode MessageExecuted event as AddFeeAmount",
+		},
+		{
+			name:  "hex integer",
+			json:  `{"amount": "0x10"}`,
+			value: &TeleporterFeeInfo{},
+			err:   `invalid decimal integer "0x10"`,
+		},
+		{
+			name:  "number integer",
+			json:  `{"amount": 16}`,
+			value: &TeleporterFeeInfo{},
+			err:   "big integer must be a decimal string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.json), tt.value)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
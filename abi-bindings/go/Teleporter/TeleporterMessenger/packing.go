@@ -9,7 +9,9 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/subnet-evm/accounts/abi"
+	teleporterUtils "github.com/ava-labs/teleporter/utils/teleporter-utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 )
 
@@ -70,6 +72,34 @@ func UnpackTeleporterMessage(messageBytes []byte) (*TeleporterMessage, error) {
 	return &teleporterMessage.TeleporterMessage, nil
 }
 
+// HashTeleporterMessage returns the keccak256 hash of the ABI encoded message, as stored by the TeleporterMessenger
+// contract for each message it sends and returned by getMessageHash
+func HashTeleporterMessage(message TeleporterMessage) (common.Hash, error) {
+	messageBytes, err := PackTeleporterMessage(message)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(messageBytes), nil
+}
+
+// CalculateTeleporterMessageID returns the ID of the message sent from the source blockchain by the
+// TeleporterMessenger contract at teleporterAddress
+func CalculateTeleporterMessageID(
+	teleporterAddress common.Address,
+	sourceBlockchainID ids.ID,
+	message TeleporterMessage,
+) (ids.ID, error) {
+	if message.MessageNonce == nil {
+		return ids.ID{}, fmt.Errorf("missing message nonce")
+	}
+	return teleporterUtils.CalculateMessageID(
+		teleporterAddress,
+		sourceBlockchainID,
+		message.DestinationBlockchainID,
+		message.MessageNonce,
+	)
+}
+
 func PackSendCrossChainMessage(input TeleporterMessageInput) ([]byte, error) {
 	abi, err := TeleporterMessengerMetaData.GetAbi()
 	if err != nil {
@@ -143,3 +143,84 @@ func TestUnpackEvent(t *testing.T) {
 		})
 	}
 }
+
+// Test vectors for the messages of GetMessageHashTests.t.sol, built with the defaults of
+// TeleporterMessengerTest.t.sol. Forge deploys the test contract at 0x7FA9385bE102ac3EAc297483Dd6233D62b3e1496,
+// and the test contract deploys the TeleporterMessenger contract first, at
+// 0x5615dEB798BB3E4dFa0139dFa1b3D433Cc23b72f, with the blockchain ID DEFAULT_DESTINATION_BLOCKCHAIN_ID.
+//
+// The expected values were read from the compiled TeleporterMessenger contract, run at the same addresses on a
+// simulated backend with that blockchain ID. The sent message was sent through sendCrossChainMessage, and its ID
+// and hash are those of its SendCrossChainMessage event and getMessageHash. The ID of the received message is
+// the result of calculateMessageID, and its hash is the one retryMessageExecution accepts for the message once
+// it is stored as the message's failed message hash.
+func TestHashTeleporterMessage(t *testing.T) {
+	var (
+		testContractAddress       = common.HexToAddress("0x7FA9385bE102ac3EAc297483Dd6233D62b3e1496")
+		teleporterAddress         = common.HexToAddress("0x5615dEB798BB3E4dFa0139dFa1b3D433Cc23b72f")
+		defaultSourceBlockchainID = ids.ID(
+			common.HexToHash("0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd"))
+		defaultDestinationBlockchainID = ids.ID(
+			common.HexToHash("0x1234567812345678123456781234567812345678123456781234567812345678"))
+	)
+	newMessage := func(messageNonce int64, receipts []TeleporterMessageReceipt) TeleporterMessage {
+		return TeleporterMessage{
+			MessageNonce:            big.NewInt(messageNonce),
+			OriginSenderAddress:     testContractAddress,
+			DestinationBlockchainID: defaultDestinationBlockchainID,
+			DestinationAddress:      common.HexToAddress("0xd54e3E251b9b0EEd3ed70A858e927bbC2659587d"),
+			RequiredGasLimit:        big.NewInt(1e6),
+			AllowedRelayerAddresses: []common.Address{},
+			Receipts:                receipts,
+			Message:                 []byte{},
+		}
+	}
+
+	var (
+		tests = []struct {
+			name               string
+			message            TeleporterMessage
+			sourceBlockchainID ids.ID
+			hash               common.Hash
+			messageID          common.Hash
+		}{
+			{
+				// The message sent by testSuccess, from the destination chain to itself
+				name:               "sent message",
+				message:            newMessage(1, []TeleporterMessageReceipt{}),
+				sourceBlockchainID: defaultDestinationBlockchainID,
+				hash:               common.HexToHash("0x11d26b57034a2dc402cc42d0780b950d9247650a9e7ccc33f04110ec83cc78ce"),
+				messageID:          common.HexToHash("0xe6b6934a1ee25b99e2dc58d9c73f42ffb66687c9a1ba7194192f45d50c8e6c58"),
+			},
+			{
+				// The message received by testMessageAlreadyReceived, with the receipt of the sent message. It is
+				// received from DEFAULT_DESTINATION_BLOCKCHAIN_ID, the blockchain ID of the contract itself.
+				name: "received message",
+				message: newMessage(43, []TeleporterMessageReceipt{
+					{
+						ReceivedMessageNonce: big.NewInt(1),
+						RelayerRewardAddress: common.HexToAddress("0xA66884fAdC0D4d7B7eedcF61Eb863Ff413bB6234"),
+					},
+				}),
+				sourceBlockchainID: defaultDestinationBlockchainID,
+				hash:               common.HexToHash("0xbd5697c07495f5066426c4b6c6c5d072b91e5e7f6b177a3d2463632bf032a8d5"),
+				messageID:          common.HexToHash("0x3461fd1b5a916a66075c2c5198c42edd780ef0dc8ab23d91c2809a8946d3c939"),
+			},
+		}
+	)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hash, err := HashTeleporterMessage(test.message)
+			require.NoError(t, err)
+			require.Equal(t, test.hash, hash)
+
+			messageID, err := CalculateTeleporterMessageID(teleporterAddress, test.sourceBlockchainID, test.message)
+			require.NoError(t, err)
+			require.Equal(t, test.messageID, common.Hash(messageID))
+		})
+	}
+
+	_, err := CalculateTeleporterMessageID(teleporterAddress, defaultSourceBlockchainID, TeleporterMessage{})
+	require.ErrorContains(t, err, "missing message nonce")
+}
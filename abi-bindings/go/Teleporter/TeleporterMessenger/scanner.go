@@ -0,0 +1,225 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package teleportermessenger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultLogScannerChunkSize is the default maximum number of blocks a LogScanner queries logs for in a
+// single request, which is within the limits of public RPC endpoints
+const DefaultLogScannerChunkSize = 2048
+
+// ErrReorg is returned by a LogScanner when the blocks it scans do not build on its checkpoint
+var ErrReorg = errors.New("chain reorganized below the checkpoint")
+
+// Checkpoint is the last block scanned by a LogScanner. Consumers persist it to resume scanning after the block.
+type Checkpoint struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+}
+
+// LogScannerBackend is the subset of an RPC client used by a LogScanner
+type LogScannerBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, q interfaces.FilterQuery) ([]types.Log, error)
+}
+
+type LogScannerConfig struct {
+	// TeleporterAddress is the address of the TeleporterMessenger contract whose logs are scanned
+	TeleporterAddress common.Address
+	// FromBlock is the first block scanned, unless Checkpoint is set
+	FromBlock uint64
+	// ToBlock is the last block scanned. If it is zero, or ahead of the final blocks, blocks are scanned up to
+	// the latest final block.
+	ToBlock uint64
+	// ChunkSize is the maximum number of blocks logs are queried for in a single request,
+	// DefaultLogScannerChunkSize if it is zero
+	ChunkSize uint64
+	// Confirmations is the number of blocks behind the latest block that blocks are considered final. Blocks
+	// are final once accepted on Avalanche, so it only needs to be set for RPC endpoints that serve blocks
+	// before they are accepted.
+	Confirmations uint64
+	// Checkpoint, if set, is the checkpoint to resume scanning after, and FromBlock is ignored
+	Checkpoint *Checkpoint
+}
+
+// LogScanner iterates over the events emitted by a TeleporterMessenger contract in a range of final blocks,
+// querying their logs in chunks of blocks. Before scanning each chunk, the scanner checks that it builds on
+// the last scanned block, and fails with ErrReorg otherwise.
+//
+// The checkpoint of the scanner advances to the end of a chunk once all of the chunk's events have been
+// returned by Next, so events returned since the last checkpoint are returned again after resuming from it.
+type LogScanner struct {
+	backend LogScannerBackend
+	config  LogScannerConfig
+
+	nextBlock uint64
+	// The latest final block, read before the first chunk is scanned and refreshed once it has been scanned
+	head       uint64
+	headRead   bool
+	checkpoint *Checkpoint
+
+	// The events of the current chunk that have not been returned yet, and the checkpoint at the
+	// end of the chunk, which is set once they have all been returned
+	pending  []TeleporterEvent
+	chunkEnd *Checkpoint
+	event    TeleporterEvent
+	done     bool
+	err      error
+}
+
+// NewLogScanner returns a scanner of the events emitted by the TeleporterMessenger contract in the configured
+// range of blocks
+func NewLogScanner(backend LogScannerBackend, config LogScannerConfig) (*LogScanner, error) {
+	if config.ChunkSize == 0 {
+		config.ChunkSize = DefaultLogScannerChunkSize
+	}
+	s := &LogScanner{
+		backend:   backend,
+		config:    config,
+		nextBlock: config.FromBlock,
+	}
+	if config.Checkpoint != nil {
+		checkpoint := *config.Checkpoint
+		s.checkpoint = &checkpoint
+		s.nextBlock = checkpoint.BlockNumber + 1
+	}
+	if config.ToBlock != 0 && config.ToBlock < s.nextBlock {
+		return nil, fmt.Errorf("to block %d is before the first block to scan %d", config.ToBlock, s.nextBlock)
+	}
+	return s, nil
+}
+
+// Next advances the scanner to the next event, scanning the next chunk of blocks once the events of the
+// current chunk have all been returned. It returns false once the range has been scanned, or if scanning
+// fails, in which case Error returns the failure.
+func (s *LogScanner) Next(ctx context.Context) bool {
+	for {
+		if s.err != nil {
+			return false
+		}
+		if len(s.pending) > 0 {
+			s.event, s.pending = s.pending[0], s.pending[1:]
+			if len(s.pending) == 0 {
+				s.checkpoint = s.chunkEnd
+			}
+			return true
+		}
+		s.event = nil
+		if s.done {
+			return false
+		}
+		if err := s.scanChunk(ctx); err != nil {
+			s.err = err
+			return false
+		}
+	}
+}
+
+// Event returns the event the scanner is at
+func (s *LogScanner) Event() TeleporterEvent {
+	return s.event
+}
+
+// Checkpoint returns the last block whose events have all been returned by Next, or nil if no block has
+// been scanned yet
+func (s *LogScanner) Checkpoint() *Checkpoint {
+	if s.checkpoint == nil {
+		return nil
+	}
+	checkpoint := *s.checkpoint
+	return &checkpoint
+}
+
+// Error returns any error that stopped the scanner
+func (s *LogScanner) Error() error {
+	return s.err
+}
+
+// scanChunk queries and decodes the logs of the next chunk of blocks, or marks the scanner as done if the
+// range has been scanned
+func (s *LogScanner) scanChunk(ctx context.Context) error {
+	if !s.headRead || s.nextBlock > s.head {
+		latest, err := s.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		if latest.Number.Uint64() < s.config.Confirmations {
+			s.done = true
+			return nil
+		}
+		s.head, s.headRead = latest.Number.Uint64()-s.config.Confirmations, true
+	}
+	toBlock := s.head
+	if s.config.ToBlock != 0 && s.config.ToBlock < toBlock {
+		toBlock = s.config.ToBlock
+	}
+	if s.nextBlock > toBlock {
+		s.done = true
+		return nil
+	}
+	start := s.nextBlock
+	end := start + s.config.ChunkSize - 1
+	if end > toBlock || end < start {
+		end = toBlock
+	}
+
+	if s.checkpoint != nil {
+		header, err := s.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(start))
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", start, err)
+		}
+		if header.ParentHash != s.checkpoint.BlockHash {
+			return fmt.Errorf("%w: block %d does not build on block %d %s",
+				ErrReorg, start, s.checkpoint.BlockNumber, s.checkpoint.BlockHash.Hex())
+		}
+	}
+	logs, err := s.backend.FilterLogs(ctx, interfaces.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(start),
+		ToBlock:   new(big.Int).SetUint64(end),
+		Addresses: []common.Address{s.config.TeleporterAddress},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get logs from block %d to %d: %w", start, end, err)
+	}
+	header, err := s.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(end))
+	if err != nil {
+		return fmt.Errorf("failed to get block %d: %w", end, err)
+	}
+
+	var events []TeleporterEvent
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		if log.BlockNumber == end && log.BlockHash != header.Hash() {
+			return fmt.Errorf("%w: log of block %d is not from block %s", ErrReorg, end, header.Hash().Hex())
+		}
+		event, err := DecodeLog(log)
+		if errors.Is(err, ErrUnknownEvent) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode log %d of transaction %s: %w", log.Index, log.TxHash.Hex(), err)
+		}
+		events = append(events, event)
+	}
+
+	checkpoint := &Checkpoint{BlockNumber: end, BlockHash: header.Hash()}
+	s.nextBlock = end + 1
+	if len(events) == 0 {
+		s.checkpoint = checkpoint
+	} else {
+		s.pending, s.chunkEnd = events, checkpoint
+	}
+	return nil
+}
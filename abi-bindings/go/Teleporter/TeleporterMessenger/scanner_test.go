@@ -0,0 +1,169 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package teleportermessenger
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var testScannerTeleporterAddress = common.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf")
+
+// testScannerBackend serves a chain of headers and the logs emitted in them, and records the
+// block ranges logs are queried for
+type testScannerBackend struct {
+	headers []*types.Header
+	logs    []types.Log
+	queries [][2]uint64
+}
+
+// newTestScannerBackend returns a backend with a chain of the given number of blocks, with a
+// MessageExecuted event for each message ID at the block number it is mapped to
+func newTestScannerBackend(t *testing.T, numBlocks int, messages map[uint64][]ids.ID) *testScannerBackend {
+	b := &testScannerBackend{}
+	var parentHash common.Hash
+	for i := 0; i < numBlocks; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), ParentHash: parentHash}
+		b.headers = append(b.headers, header)
+		parentHash = header.Hash()
+	}
+
+	teleporterABI, err := TeleporterMessengerMetaData.GetAbi()
+	require.NoError(t, err)
+	for blockNumber := uint64(0); blockNumber < uint64(numBlocks); blockNumber++ {
+		for _, messageID := range messages[blockNumber] {
+			topics, data, err := teleporterABI.PackEvent(MessageExecuted.String(), messageID, ids.ID{1})
+			require.NoError(t, err)
+			b.logs = append(b.logs, types.Log{
+				Address:     testScannerTeleporterAddress,
+				Topics:      topics,
+				Data:        data,
+				BlockNumber: blockNumber,
+				BlockHash:   b.headers[blockNumber].Hash(),
+			})
+		}
+	}
+	return b
+}
+
+func (b *testScannerBackend) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return b.headers[len(b.headers)-1], nil
+	}
+	return b.headers[number.Uint64()], nil
+}
+
+func (b *testScannerBackend) FilterLogs(_ context.Context, q interfaces.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	b.queries = append(b.queries, [2]uint64{from, to})
+	var logs []types.Log
+	for _, log := range b.logs {
+		if log.BlockNumber >= from && log.BlockNumber <= to && log.Address == q.Addresses[0] {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// scanMessageIDs returns the message IDs of the events returned by the scanner, along with its
+// checkpoint after each event
+func scanMessageIDs(t *testing.T, scanner *LogScanner) ([]ids.ID, []uint64) {
+	var (
+		messageIDs  []ids.ID
+		checkpoints []uint64
+	)
+	for scanner.Next(context.Background()) {
+		messageIDs = append(messageIDs, scanner.Event().GetMessageID())
+		checkpoint := uint64(0)
+		if scanner.Checkpoint() != nil {
+			checkpoint = scanner.Checkpoint().BlockNumber
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	require.NoError(t, scanner.Error())
+	return messageIDs, checkpoints
+}
+
+func TestLogScanner(t *testing.T) {
+	messageA, messageB, messageC, messageD := ids.ID{1}, ids.ID{2}, ids.ID{3}, ids.ID{4}
+	backend := newTestScannerBackend(t, 12, map[uint64][]ids.ID{
+		2:  {messageA},
+		5:  {messageB, messageC},
+		9:  {messageD},
+		11: {ids.ID{5}},
+	})
+	// A log of another contract, and a log that is not a Teleporter event
+	backend.logs = append(backend.logs,
+		types.Log{Address: common.HexToAddress("0x1"), Topics: backend.logs[0].Topics, BlockNumber: 3},
+		types.Log{Address: testScannerTeleporterAddress, Topics: []common.Hash{{1}}, BlockNumber: 4},
+	)
+
+	// Block 11 is not final with one confirmation
+	scanner, err := NewLogScanner(backend, LogScannerConfig{
+		TeleporterAddress: testScannerTeleporterAddress,
+		ChunkSize:         3,
+		Confirmations:     1,
+	})
+	require.NoError(t, err)
+	require.Nil(t, scanner.Checkpoint())
+
+	messageIDs, checkpoints := scanMessageIDs(t, scanner)
+	require.Equal(t, []ids.ID{messageA, messageB, messageC, messageD}, messageIDs)
+	// The checkpoint reaches the end of a chunk once all of its events have been returned
+	require.Equal(t, []uint64{2, 2, 5, 10}, checkpoints)
+	require.Equal(t, [][2]uint64{{0, 2}, {3, 5}, {6, 8}, {9, 10}}, backend.queries)
+	require.Equal(t, &Checkpoint{BlockNumber: 10, BlockHash: backend.headers[10].Hash()}, scanner.Checkpoint())
+	require.False(t, scanner.Next(context.Background()))
+
+	t.Run("resume", func(t *testing.T) {
+		backend.queries = nil
+		scanner, err := NewLogScanner(backend, LogScannerConfig{
+			TeleporterAddress: testScannerTeleporterAddress,
+			Checkpoint:        &Checkpoint{BlockNumber: 5, BlockHash: backend.headers[5].Hash()},
+		})
+		require.NoError(t, err)
+
+		messageIDs, _ := scanMessageIDs(t, scanner)
+		require.Equal(t, []ids.ID{messageD, {5}}, messageIDs)
+		require.Equal(t, [][2]uint64{{6, 11}}, backend.queries)
+	})
+
+	t.Run("to block", func(t *testing.T) {
+		scanner, err := NewLogScanner(backend, LogScannerConfig{
+			TeleporterAddress: testScannerTeleporterAddress,
+			FromBlock:         3,
+			ToBlock:           6,
+		})
+		require.NoError(t, err)
+
+		messageIDs, _ := scanMessageIDs(t, scanner)
+		require.Equal(t, []ids.ID{messageB, messageC}, messageIDs)
+		require.Equal(t, uint64(6), scanner.Checkpoint().BlockNumber)
+
+		_, err = NewLogScanner(backend, LogScannerConfig{
+			TeleporterAddress: testScannerTeleporterAddress,
+			Checkpoint:        &Checkpoint{BlockNumber: 6, BlockHash: backend.headers[6].Hash()},
+			ToBlock:           6,
+		})
+		require.ErrorContains(t, err, "to block 6 is before the first block to scan 7")
+	})
+
+	t.Run("reorg", func(t *testing.T) {
+		scanner, err := NewLogScanner(backend, LogScannerConfig{
+			TeleporterAddress: testScannerTeleporterAddress,
+			Checkpoint:        &Checkpoint{BlockNumber: 5, BlockHash: common.Hash{1}},
+		})
+		require.NoError(t, err)
+		require.False(t, scanner.Next(context.Background()))
+		require.ErrorIs(t, scanner.Error(), ErrReorg)
+		require.Nil(t, scanner.Event())
+	})
+}
@@ -0,0 +1,173 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package teleportermessenger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// MaxReceiptsBatchSize is the maximum number of receipts the TeleporterMessenger contract attaches to a
+	// message sent with sendCrossChainMessage, defined in ReceiptQueue.sol. Messages sent with
+	// sendSpecifiedReceipts carry all the specified receipts, so they are not bound by it.
+	MaxReceiptsBatchSize = 5
+
+	// addressedCallOverhead is the size of the AddressedCall payload of a Warp message sent by the
+	// TeleporterMessenger contract, excluding the encoded TeleporterMessage: the codec version, the type ID,
+	// and the length prefixes of the 20 byte source address and of the message.
+	addressedCallOverhead = wrappers.ShortLen + wrappers.IntLen +
+		wrappers.IntLen + common.AddressLength + wrappers.IntLen
+
+	// MaxMessageSize is the maximum size of an encoded TeleporterMessage. The message is sent in an AddressedCall
+	// payload, which the Warp precompile fails to encode if it is larger than payload.MaxMessageSize.
+	MaxMessageSize = payload.MaxMessageSize - addressedCallOverhead
+)
+
+var (
+	ErrZeroDestinationBlockchainID = errors.New("zero destination blockchain ID")
+	ErrInvalidRequiredGasLimit     = errors.New("required gas limit must fit in a uint64")
+	ErrZeroAllowedRelayer          = errors.New("zero allowed relayer address")
+	ErrDuplicateAllowedRelayer     = errors.New("duplicate allowed relayer address")
+	ErrZeroFeeTokenAddress         = errors.New("zero fee token address with a non-zero fee amount")
+	ErrMessageTooLarge             = errors.New("encoded message too large")
+	ErrInvalidUint256              = errors.New("value must fit in a uint256")
+)
+
+// maxAddress is the address that costs the most calldata gas
+var maxAddress = common.BytesToAddress(bytes.Repeat([]byte{0xff}, common.AddressLength))
+
+// MessageAnalysis describes the ABI encoding of a TeleporterMessage, as sent in a Warp message
+type MessageAnalysis struct {
+	// Size is the length in bytes of the encoded message
+	Size int
+	// Receipts is the number of receipts the message carries
+	Receipts int
+	// CalldataGas is the gas charged for the encoded message as transaction calldata, as when the message is
+	// passed to retrySendCrossChainMessage or retryMessageExecution
+	CalldataGas uint64
+}
+
+// ValidateTeleporterMessage checks the message against the invariants the TeleporterMessenger contract relies on,
+// and returns the analysis of its encoding along with all the violated invariants joined in a single error.
+// The analysis is nil if the message cannot be encoded.
+func ValidateTeleporterMessage(message TeleporterMessage) (*MessageAnalysis, error) {
+	var errs []error
+	encodable := true
+	if !isUint256(message.MessageNonce) {
+		errs = append(errs, fmt.Errorf("%w: message nonce %v", ErrInvalidUint256, message.MessageNonce))
+		encodable = false
+	}
+	if message.DestinationBlockchainID == [32]byte{} {
+		errs = append(errs, ErrZeroDestinationBlockchainID)
+	}
+	if message.RequiredGasLimit == nil || message.RequiredGasLimit.Sign() < 0 || !message.RequiredGasLimit.IsUint64() {
+		errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidRequiredGasLimit, message.RequiredGasLimit))
+		encodable = encodable && isUint256(message.RequiredGasLimit)
+	}
+	errs = append(errs, validateAllowedRelayers(message.AllowedRelayerAddresses)...)
+	for i, receipt := range message.Receipts {
+		if !isUint256(receipt.ReceivedMessageNonce) {
+			errs = append(errs, fmt.Errorf("%w: nonce of receipt %d %v",
+				ErrInvalidUint256, i, receipt.ReceivedMessageNonce))
+			encodable = false
+		}
+	}
+	if !encodable {
+		return nil, errors.Join(errs...)
+	}
+
+	encoded, err := PackTeleporterMessage(message)
+	if err != nil {
+		return nil, errors.Join(append(errs, err)...)
+	}
+	analysis := &MessageAnalysis{
+		Size:        len(encoded),
+		Receipts:    len(message.Receipts),
+		CalldataGas: calldataGas(encoded),
+	}
+	if analysis.Size > MaxMessageSize {
+		errs = append(errs, fmt.Errorf("%w: %d bytes, the limit is %d bytes",
+			ErrMessageTooLarge, analysis.Size, MaxMessageSize))
+	}
+	return analysis, errors.Join(errs...)
+}
+
+// ValidateMessageInput checks the input to sendCrossChainMessage against the invariants the TeleporterMessenger
+// contract relies on, and returns the analysis of the encoding of the message it is sent as, along with all the
+// violated invariants joined in a single error. The nonce, origin sender and receipts of the message are set by
+// the contract, so the analysis is an upper bound computed with the values that encode to the most bytes and cost
+// the most gas.
+func ValidateMessageInput(input TeleporterMessageInput) (*MessageAnalysis, error) {
+	var errs []error
+	if amount := input.FeeInfo.Amount; amount != nil {
+		if !isUint256(amount) {
+			errs = append(errs, fmt.Errorf("%w: fee amount %s", ErrInvalidUint256, amount))
+		} else if amount.Sign() > 0 && input.FeeInfo.FeeTokenAddress == (common.Address{}) {
+			errs = append(errs, ErrZeroFeeTokenAddress)
+		}
+	}
+
+	receipts := make([]TeleporterMessageReceipt, MaxReceiptsBatchSize)
+	for i := range receipts {
+		receipts[i] = TeleporterMessageReceipt{
+			ReceivedMessageNonce: abi.MaxUint256,
+			RelayerRewardAddress: maxAddress,
+		}
+	}
+	analysis, err := ValidateTeleporterMessage(TeleporterMessage{
+		MessageNonce:            abi.MaxUint256,
+		OriginSenderAddress:     maxAddress,
+		DestinationBlockchainID: input.DestinationBlockchainID,
+		DestinationAddress:      input.DestinationAddress,
+		RequiredGasLimit:        input.RequiredGasLimit,
+		AllowedRelayerAddresses: input.AllowedRelayerAddresses,
+		Receipts:                receipts,
+		Message:                 input.Message,
+	})
+	return analysis, errors.Join(append(errs, err)...)
+}
+
+// validateAllowedRelayers checks that the allowed relayers are neither zero nor duplicated
+func validateAllowedRelayers(allowedRelayers []common.Address) []error {
+	var errs []error
+	seen := make(map[common.Address]struct{}, len(allowedRelayers))
+	for _, relayer := range allowedRelayers {
+		if relayer == (common.Address{}) {
+			errs = append(errs, ErrZeroAllowedRelayer)
+			continue
+		}
+		if _, ok := seen[relayer]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrDuplicateAllowedRelayer, relayer.Hex()))
+			continue
+		}
+		seen[relayer] = struct{}{}
+	}
+	return errs
+}
+
+func isUint256(x *big.Int) bool {
+	return x != nil && x.Sign() >= 0 && x.BitLen() <= 256
+}
+
+// calldataGas returns the gas charged for the data as transaction calldata
+func calldataGas(data []byte) uint64 {
+	var gas uint64
+	for _, b := range data {
+		if b == 0 {
+			gas += params.TxDataZeroGas
+		} else {
+			gas += params.TxDataNonZeroGasEIP2028
+		}
+	}
+	return gas
+}
@@ -0,0 +1,171 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package teleportermessenger
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTeleporterMessage(t *testing.T) {
+	relayer := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+
+	var tests = []struct {
+		name      string
+		modify    func(m *TeleporterMessage)
+		errs      []error
+		encodable bool
+	}{
+		{
+			name:      "valid",
+			modify:    func(m *TeleporterMessage) {},
+			encodable: true,
+		},
+		{
+			name: "zero destination blockchain ID",
+			modify: func(m *TeleporterMessage) {
+				m.DestinationBlockchainID = ids.Empty
+			},
+			errs:      []error{ErrZeroDestinationBlockchainID},
+			encodable: true,
+		},
+		{
+			name: "required gas limit too high",
+			modify: func(m *TeleporterMessage) {
+				m.RequiredGasLimit = new(big.Int).Lsh(common.Big1, 64)
+			},
+			errs:      []error{ErrInvalidRequiredGasLimit},
+			encodable: true,
+		},
+		{
+			name: "missing required gas limit",
+			modify: func(m *TeleporterMessage) {
+				m.RequiredGasLimit = nil
+			},
+			errs: []error{ErrInvalidRequiredGasLimit},
+		},
+		{
+			name: "zero and duplicate allowed relayers",
+			modify: func(m *TeleporterMessage) {
+				m.AllowedRelayerAddresses = []common.Address{relayer, {}, relayer}
+			},
+			errs:      []error{ErrZeroAllowedRelayer, ErrDuplicateAllowedRelayer},
+			encodable: true,
+		},
+		{
+			name: "more receipts than a batch",
+			modify: func(m *TeleporterMessage) {
+				for len(m.Receipts) <= MaxReceiptsBatchSize {
+					m.Receipts = append(m.Receipts, m.Receipts[0])
+				}
+			},
+			encodable: true,
+		},
+		{
+			name: "negative receipt nonce",
+			modify: func(m *TeleporterMessage) {
+				m.Receipts[0].ReceivedMessageNonce = big.NewInt(-1)
+			},
+			errs: []error{ErrInvalidUint256},
+		},
+		{
+			name: "largest message",
+			modify: func(m *TeleporterMessage) {
+				m.Message = make([]byte, maxTestMessageLength(t, *m))
+			},
+			encodable: true,
+		},
+		{
+			name: "message too large",
+			modify: func(m *TeleporterMessage) {
+				m.Message = make([]byte, maxTestMessageLength(t, *m)+1)
+			},
+			errs:      []error{ErrMessageTooLarge},
+			encodable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := createTestTeleporterMessage(big.NewInt(4))
+			tt.modify(&message)
+
+			analysis, err := ValidateTeleporterMessage(message)
+			if len(tt.errs) == 0 {
+				require.NoError(t, err)
+			}
+			for _, expected := range tt.errs {
+				require.ErrorIs(t, err, expected)
+			}
+			if !tt.encodable {
+				require.Nil(t, analysis)
+				return
+			}
+			encoded, err := PackTeleporterMessage(message)
+			require.NoError(t, err)
+			require.Equal(t, len(encoded), analysis.Size)
+			require.Equal(t, len(message.Receipts), analysis.Receipts)
+			require.Equal(t, calldataGas(encoded), analysis.CalldataGas)
+		})
+	}
+}
+
+// maxTestMessageLength returns the length of the longest payload of the message for which the encoded message
+// is at most MaxMessageSize bytes. The encoded payload is padded to a multiple of 32 bytes, so a payload one
+// byte longer is encoded in 32 more bytes.
+func maxTestMessageLength(t *testing.T, message TeleporterMessage) int {
+	message.Message = nil
+	encoded, err := PackTeleporterMessage(message)
+	require.NoError(t, err)
+	return (MaxMessageSize - len(encoded)) / 32 * 32
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	// The largest message fits in the AddressedCall payload of a Warp message sent by the TeleporterMessenger
+	sourceAddress := common.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf").Bytes()
+	addressedCall, err := payload.NewAddressedCall(sourceAddress, make([]byte, MaxMessageSize))
+	require.NoError(t, err)
+	require.Len(t, addressedCall.Bytes(), payload.MaxMessageSize)
+
+	_, err = payload.NewAddressedCall(sourceAddress, make([]byte, MaxMessageSize+1))
+	require.Error(t, err)
+}
+
+func TestValidateMessageInput(t *testing.T) {
+	input := TeleporterMessageInput{
+		DestinationBlockchainID: ids.ID{1, 2, 3, 4},
+		DestinationAddress:      common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		FeeInfo:                 TeleporterFeeInfo{Amount: big.NewInt(0)},
+		RequiredGasLimit:        big.NewInt(100_000),
+		Message:                 []byte{1, 2, 3, 4},
+	}
+	analysis, err := ValidateMessageInput(input)
+	require.NoError(t, err)
+
+	// The analysis bounds the message sent with any nonce, sender and receipts
+	message := createTestTeleporterMessage(big.NewInt(4))
+	message.AllowedRelayerAddresses = nil
+	message.Message = input.Message
+	encoded, err := PackTeleporterMessage(message)
+	require.NoError(t, err)
+	require.Greater(t, analysis.Size, len(encoded))
+	require.Equal(t, MaxReceiptsBatchSize, analysis.Receipts)
+	require.Greater(t, analysis.CalldataGas, calldataGas(encoded))
+
+	input.FeeInfo.Amount = big.NewInt(1)
+	input.DestinationBlockchainID = ids.Empty
+	_, err = ValidateMessageInput(input)
+	require.ErrorIs(t, err, ErrZeroFeeTokenAddress)
+	require.ErrorIs(t, err, ErrZeroDestinationBlockchainID)
+}
+
+func TestCalldataGas(t *testing.T) {
+	require.Equal(t, uint64(0), calldataGas(nil))
+	require.Equal(t, uint64(4+16+4), calldataGas([]byte{0, 1, 0}))
+}
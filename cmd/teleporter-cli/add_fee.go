@@ -0,0 +1,141 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	addFeeToken  string
+	addFeeAmount string
+)
+
+type addFeeOutput struct {
+	TransactionHash string         `json:"transactionHash" yaml:"transactionHash"`
+	MessageID       string         `json:"messageID" yaml:"messageID"`
+	UpdatedFeeInfo  *feeInfoOutput `json:"updatedFeeInfo" yaml:"updatedFeeInfo"`
+	Event           *eventOutput   `json:"event" yaml:"event"`
+}
+
+var addFeeCmd = &cobra.Command{
+	Use:   "add-fee --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS --token ADDRESS --amount AMOUNT MESSAGE_ID",
+	Short: "Adds to the relayer fee of a sent message",
+	Long: `Adds to the relayer fee of a message that has been sent but not yet receipted, to
+incentivize relayers to deliver it. The --rpc flag must point to the source chain of
+the message. The fee token must match the token of the message's original fee, and
+the TeleporterMessenger contract is first approved to spend the amount if needed.
+The updated fee info is parsed from the AddFeeAmount event and printed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		messageID, err := parseID(args[0])
+		cobra.CheckErr(err)
+		token, err := parseAddress(addFeeToken)
+		cobra.CheckErr(err)
+		amount, err := parseBigInt(addFeeAmount)
+		cobra.CheckErr(err)
+
+		ctx := context.Background()
+		chainID, err := client.ChainID(ctx)
+		cobra.CheckErr(err)
+		opts, err := newTransactOpts(ctx, &signerKeyFlags, chainID)
+		cobra.CheckErr(err)
+
+		event, err := addFeeAmountToMessage(ctx, client, opts, teleporterAddress, messageID, token, amount)
+		cobra.CheckErr(err)
+
+		if !isTableOutput() {
+			eventOut, err := newEventOutput(event, &event.Raw)
+			cobra.CheckErr(err)
+			cobra.CheckErr(printOutput(cmd, addFeeOutput{
+				TransactionHash: event.Raw.TxHash.Hex(),
+				MessageID:       messageID.String(),
+				UpdatedFeeInfo:  newFeeInfoOutput(event.UpdatedFeeInfo),
+				Event:           eventOut,
+			}))
+			return
+		}
+		logger.Info("Added fee amount",
+			zap.String("txHash", event.Raw.TxHash.Hex()),
+			zap.String("messageID", messageID.String()),
+			zap.String("feeTokenAddress", event.UpdatedFeeInfo.FeeTokenAddress.Hex()),
+			zap.String("amount", event.UpdatedFeeInfo.Amount.String()))
+		cmd.Println("Add fee command ran successfully")
+	},
+}
+
+// addFeeAmountToMessage checks that the fee can be added to the message, approves the amount if
+// necessary, adds it to the message's fee, and returns the AddFeeAmount event emitted.
+func addFeeAmountToMessage(
+	ctx context.Context,
+	backend contractBackend,
+	opts *bind.TransactOpts,
+	teleporterAddress common.Address,
+	messageID ids.ID,
+	token common.Address,
+	amount *big.Int,
+) (*teleportermessenger.TeleporterMessengerAddFeeAmount, error) {
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("fee amount must be positive")
+	}
+	if token == (common.Address{}) {
+		return nil, fmt.Errorf("fee token address must be provided")
+	}
+
+	teleporter, err := teleportermessenger.NewTeleporterMessenger(teleporterAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	// Check the conditions required by addFeeAmount up front, to fail with a clearer error than a revert.
+	callOpts := &bind.CallOpts{Context: ctx}
+	messageHash, err := teleporter.GetMessageHash(callOpts, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if messageHash == [32]byte{} {
+		return nil, fmt.Errorf("message %s not found, it may never have been sent or already been receipted", messageID)
+	}
+	feeTokenAddress, _, err := teleporter.GetFeeInfo(callOpts, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if feeTokenAddress != token {
+		return nil, fmt.Errorf("fee token %s does not match the message's fee token %s",
+			token.Hex(), feeTokenAddress.Hex())
+	}
+
+	if err := ensureERC20Allowance(ctx, backend, opts, token, teleporterAddress, amount); err != nil {
+		return nil, err
+	}
+	tx, err := teleporter.AddFeeAmount(opts, messageID, token, amount)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := waitForSuccess(ctx, backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	return findEvent(receipt, teleporterAddress, teleporter.ParseAddFeeAmount)
+}
+
+func init() {
+	rootCmd.AddCommand(addFeeCmd)
+	addRPCFlags(addFeeCmd)
+	addKeyFlags(addFeeCmd, &signerKeyFlags)
+	addFeeCmd.Flags().StringVar(&addFeeToken, "token", "", "Address of the message's fee token")
+	addFeeCmd.Flags().StringVar(&addFeeAmount, "amount", "", "Amount to add to the message's fee")
+	err := addFeeCmd.MarkFlagRequired("token")
+	cobra.CheckErr(err)
+	err = addFeeCmd.MarkFlagRequired("amount")
+	cobra.CheckErr(err)
+}
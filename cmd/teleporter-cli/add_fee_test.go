@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFeeCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "no args",
+			args: []string{"add-fee"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "help",
+			args: []string{"add-fee", "--help"},
+			err:  nil,
+			out:  "Adds to the relayer fee of a message that has been sent but not yet receipted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestAddFeeAmountToMessage(t *testing.T) {
+	ctx := context.Background()
+	backend, key := newTestBackend(t)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, simulatedChainID)
+	require.NoError(t, err)
+	opts.Context = ctx
+
+	teleporterAddress, tx, _, err := teleportermessenger.DeployTeleporterMessenger(opts, backend)
+	require.NoError(t, err)
+	_, err = waitForSuccess(ctx, backend, tx)
+	require.NoError(t, err)
+
+	token := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+	var tests = []struct {
+		name   string
+		token  common.Address
+		amount *big.Int
+		err    string
+	}{
+		{
+			name:   "zero amount",
+			token:  token,
+			amount: big.NewInt(0),
+			err:    "fee amount must be positive",
+		},
+		{
+			name:   "zero token",
+			token:  common.Address{},
+			amount: big.NewInt(1),
+			err:    "fee token address must be provided",
+		},
+		{
+			name:   "message not found",
+			token:  token,
+			amount: big.NewInt(1),
+			err:    "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := addFeeAmountToMessage(
+				ctx, backend, opts, teleporterAddress, ids.GenerateTestID(), tt.token, tt.amount)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const defaultBlockChunkSize = 2048
+
+var blockChunkSize uint64
+
+type countOutput struct {
+	Name  string `json:"name" yaml:"name"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+type rangeOutput struct {
+	FromBlock         uint64        `json:"fromBlock" yaml:"fromBlock"`
+	ToBlock           uint64        `json:"toBlock" yaml:"toBlock"`
+	EventCounts       []countOutput `json:"eventCounts" yaml:"eventCounts"`
+	DestinationCounts []countOutput `json:"destinationBlockchainCounts" yaml:"destinationBlockchainCounts"`
+	Logs              []*logOutput  `json:"logs" yaml:"logs"`
+}
+
+var blockCmd = &cobra.Command{
+	Use:   "block --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS BLOCK_NUMBER",
+	Short: "Decodes every Teleporter log in a block",
+	Long: `Given a block number, this command decodes every Teleporter event and every Warp
+message sent by the TeleporterMessenger contract in the block, and prints the number
+of events of each type and the number of events per destination blockchain ID.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		blockNumber, err := strconv.ParseUint(args[0], 10, 64)
+		cobra.CheckErr(err)
+		cobra.CheckErr(runRange(cmd, blockNumber, blockNumber))
+		cmd.Println("Block command ran successfully")
+	},
+}
+
+var rangeCmd = &cobra.Command{
+	Use:   "range --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS FROM_BLOCK TO_BLOCK",
+	Short: "Decodes every Teleporter log in a range of blocks",
+	Long: `Given an inclusive range of block numbers, this command decodes every Teleporter
+event and every Warp message sent by the TeleporterMessenger contract in the range,
+and prints the number of events of each type and the number of events per
+destination blockchain ID. Logs are queried in chunks of at most --chunk-size blocks.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		fromBlock, err := strconv.ParseUint(args[0], 10, 64)
+		cobra.CheckErr(err)
+		toBlock, err := strconv.ParseUint(args[1], 10, 64)
+		cobra.CheckErr(err)
+		if fromBlock > toBlock {
+			cobra.CheckErr(fmt.Errorf("from block %d is after to block %d", fromBlock, toBlock))
+		}
+		cobra.CheckErr(runRange(cmd, fromBlock, toBlock))
+		cmd.Println("Range command ran successfully")
+	},
+}
+
+func runRange(cmd *cobra.Command, fromBlock uint64, toBlock uint64) error {
+	if blockChunkSize == 0 {
+		return fmt.Errorf("chunk size must be positive")
+	}
+	logs, err := filterLogsInChunks(
+		context.Background(),
+		client,
+		[]common.Address{teleporterAddress, warp.ContractAddress},
+		fromBlock,
+		toBlock,
+		blockChunkSize,
+	)
+	if err != nil {
+		return err
+	}
+
+	outs := []*logOutput{}
+	for _, log := range logs {
+		out, err := decodeLogOutput(log)
+		if err != nil {
+			logger.Warn("Failed to decode log",
+				zap.String("txHash", log.TxHash.Hex()),
+				zap.Uint("logIndex", log.Index),
+				zap.Error(err))
+			continue
+		}
+		if out != nil {
+			outs = append(outs, out)
+		}
+	}
+
+	rangeOut := newRangeOutput(fromBlock, toBlock, outs)
+	if !isTableOutput() {
+		return printOutput(cmd, rangeOut)
+	}
+	for _, out := range rangeOut.Logs {
+		if err := printLogOutput(cmd, out); err != nil {
+			return err
+		}
+	}
+	cmd.Printf("Blocks %d to %d:\n", fromBlock, toBlock)
+	cmd.Println("Events:")
+	for _, count := range rangeOut.EventCounts {
+		cmd.Printf("  %s: %d\n", count.Name, count.Count)
+	}
+	cmd.Println("Destination blockchain IDs:")
+	for _, count := range rangeOut.DestinationCounts {
+		cmd.Printf("  %s: %d\n", count.Name, count.Count)
+	}
+	return nil
+}
+
+// filterLogsInChunks queries the logs emitted by the addresses in the inclusive block range,
+// splitting the range into chunks of at most chunkSize blocks to stay within RPC limits.
+func filterLogsInChunks(
+	ctx context.Context,
+	reader interfaces.LogFilterer,
+	addresses []common.Address,
+	fromBlock uint64,
+	toBlock uint64,
+	chunkSize uint64,
+) ([]types.Log, error) {
+	var logs []types.Log
+	for start := fromBlock; start <= toBlock; start += chunkSize {
+		end := start + chunkSize - 1
+		if end > toBlock || end < start {
+			end = toBlock
+		}
+		chunk, err := reader.FilterLogs(ctx, interfaces.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: addresses,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get logs from block %d to %d: %w", start, end, err)
+		}
+		logs = append(logs, chunk...)
+		if end == toBlock {
+			break
+		}
+	}
+	return logs, nil
+}
+
+func newRangeOutput(fromBlock uint64, toBlock uint64, outs []*logOutput) rangeOutput {
+	eventCounts := make(map[string]int)
+	destinationCounts := make(map[string]int)
+	for _, out := range outs {
+		eventCounts[out.name()]++
+		// The Warp message of a sent message is emitted along with its SendCrossChainMessage event, so only
+		// events are counted to count each message once
+		if out.Event == nil {
+			continue
+		}
+		if destinationBlockchainID := out.destinationBlockchainID(); destinationBlockchainID != "" {
+			destinationCounts[destinationBlockchainID]++
+		}
+	}
+	return rangeOutput{
+		FromBlock:         fromBlock,
+		ToBlock:           toBlock,
+		EventCounts:       sortedCounts(eventCounts),
+		DestinationCounts: sortedCounts(destinationCounts),
+		Logs:              outs,
+	}
+}
+
+// sortedCounts returns the counts sorted by name
+func sortedCounts(counts map[string]int) []countOutput {
+	out := []countOutput{}
+	for name, count := range counts {
+		out = append(out, countOutput{Name: name, Count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func init() {
+	rootCmd.AddCommand(blockCmd, rangeCmd)
+	addRPCFlags(blockCmd)
+	addRPCFlags(rangeCmd)
+	rangeCmd.Flags().Uint64Var(&blockChunkSize, "chunk-size", defaultBlockChunkSize,
+		"Maximum number of blocks to query logs for in a single request")
+}
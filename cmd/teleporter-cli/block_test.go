@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockCmds(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "block no args",
+			args: []string{"block"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "block help",
+			args: []string{"block", "--help"},
+			err:  nil,
+			out:  "Given a block number, this command decodes every Teleporter event",
+		},
+		{
+			name: "range one arg",
+			args: []string{"range", "1"},
+			err:  fmt.Errorf("accepts 2 arg(s), received 1"),
+		},
+		{
+			name: "range help",
+			args: []string{"range", "--help"},
+			err:  nil,
+			out:  "Given an inclusive range of block numbers, this command decodes every Teleporter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestFilterLogsInChunks(t *testing.T) {
+	var logs []types.Log
+	for i := uint64(0); i <= 10; i++ {
+		logs = append(logs, types.Log{BlockNumber: i, Index: uint(i)})
+	}
+	reader := &fakeLogReader{logs: logs, latest: 10}
+
+	var tests = []struct {
+		name      string
+		fromBlock uint64
+		toBlock   uint64
+		chunkSize uint64
+	}{
+		{
+			name:      "single block",
+			fromBlock: 4,
+			toBlock:   4,
+			chunkSize: 3,
+		},
+		{
+			name:      "single chunk",
+			fromBlock: 0,
+			toBlock:   10,
+			chunkSize: 100,
+		},
+		{
+			name:      "partial last chunk",
+			fromBlock: 1,
+			toBlock:   9,
+			chunkSize: 4,
+		},
+		{
+			name:      "exact chunks",
+			fromBlock: 1,
+			toBlock:   9,
+			chunkSize: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := filterLogsInChunks(
+				context.Background(), reader, nil, tt.fromBlock, tt.toBlock, tt.chunkSize)
+			require.NoError(t, err)
+			require.Equal(t, logs[tt.fromBlock:tt.toBlock+1], filtered)
+		})
+	}
+}
+
+func TestNewRangeOutput(t *testing.T) {
+	destinationBlockchainID := ids.GenerateTestID().String()
+	message := newTeleporterMessageOutput(createTestTeleporterMessage())
+	message.DestinationBlockchainID = destinationBlockchainID
+
+	outs := []*logOutput{
+		{Event: &eventOutput{
+			Name:                    "SendCrossChainMessage",
+			DestinationBlockchainID: destinationBlockchainID,
+			Message:                 message,
+		}},
+		{WarpMessage: &warpMessageOutput{Message: message}},
+		{Event: &eventOutput{Name: "MessageExecuted"}},
+		{Event: &eventOutput{Name: "MessageExecuted"}},
+	}
+	rangeOut := newRangeOutput(1, 2, outs)
+	require.Equal(t, []countOutput{
+		{Name: "MessageExecuted", Count: 2},
+		{Name: "SendCrossChainMessage", Count: 1},
+		{Name: "SendWarpMessage", Count: 1},
+	}, rangeOut.EventCounts)
+	require.Equal(t, []countOutput{{Name: destinationBlockchainID, Count: 1}}, rangeOut.DestinationCounts)
+	require.Equal(t, outs, rangeOut.Logs)
+}
+
+func TestNewRangeOutputSendTransaction(t *testing.T) {
+	teleporter := common.HexToAddress("0x1234")
+	prevTeleporterAddress := teleporterAddress
+	teleporterAddress = teleporter
+	t.Cleanup(func() { teleporterAddress = prevTeleporterAddress })
+
+	// A send transaction emits the Warp message and the SendCrossChainMessage event of the same message
+	message := createTestTeleporterMessage()
+	messageBytes, err := teleportermessenger.PackTeleporterMessage(message)
+	require.NoError(t, err)
+	logs := []types.Log{
+		*newTestWarpLog(t, ids.ID{9}, teleporter, messageBytes),
+		newTestEventLog(t, "SendCrossChainMessage", 1,
+			[]common.Hash{common.Hash(ids.GenerateTestID()), common.Hash(message.DestinationBlockchainID)},
+			message, teleportermessenger.TeleporterFeeInfo{Amount: common.Big0}),
+	}
+	var outs []*logOutput
+	for _, log := range logs {
+		out, err := decodeLogOutput(log)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		outs = append(outs, out)
+	}
+
+	rangeOut := newRangeOutput(1, 1, outs)
+	require.Equal(t, []countOutput{
+		{Name: "SendCrossChainMessage", Count: 1},
+		{Name: "SendWarpMessage", Count: 1},
+	}, rangeOut.EventCounts)
+	require.Equal(t, []countOutput{
+		{Name: ids.ID(message.DestinationBlockchainID).String(), Count: 1},
+	}, rangeOut.DestinationCounts)
+}
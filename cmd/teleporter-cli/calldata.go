@@ -0,0 +1,263 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/predicate"
+	"github.com/ava-labs/subnet-evm/utils"
+	erc20bridge "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/ERC20Bridge/ERC20Bridge"
+	erc20tokensource "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/NativeTokenBridge/ERC20TokenSource"
+	nativetokendestination "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/NativeTokenBridge/NativeTokenDestination"
+	nativetokensource "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/NativeTokenBridge/NativeTokenSource"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	teleporterregistry "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/upgrades/TeleporterRegistry"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// messageIndexArgName is the name of the argument selecting the Warp predicate of the transaction to verify
+const messageIndexArgName = "messageIndex"
+
+// calldataContracts are the contracts whose ABIs calldata is decoded against, in order of precedence
+// for methods defined by several of them.
+var calldataContracts = []struct {
+	name     string
+	metaData *bind.MetaData
+}{
+	{name: "TeleporterMessenger", metaData: teleportermessenger.TeleporterMessengerMetaData},
+	{name: "TeleporterRegistry", metaData: teleporterregistry.TeleporterRegistryMetaData},
+	{name: "ERC20Bridge", metaData: erc20bridge.ERC20BridgeMetaData},
+	{name: "NativeTokenSource", metaData: nativetokensource.NativeTokenSourceMetaData},
+	{name: "NativeTokenDestination", metaData: nativetokendestination.NativeTokenDestinationMetaData},
+	{name: "ERC20TokenSource", metaData: erc20tokensource.ERC20TokenSourceMetaData},
+}
+
+type calldataArgOutput struct {
+	Name  string      `json:"name" yaml:"name"`
+	Type  string      `json:"type" yaml:"type"`
+	Value interface{} `json:"value" yaml:"value"`
+}
+
+type warpPredicateOutput struct {
+	Index int `json:"index" yaml:"index"`
+	// Selected is set for the predicate selected by the messageIndex argument of the call
+	Selected bool              `json:"selected,omitempty" yaml:"selected,omitempty"`
+	Message  *warpDecodeOutput `json:"message,omitempty" yaml:"message,omitempty"`
+	Error    string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type calldataOutput struct {
+	TransactionHash string                `json:"transactionHash,omitempty" yaml:"transactionHash,omitempty"`
+	To              string                `json:"to,omitempty" yaml:"to,omitempty"`
+	Selector        string                `json:"selector" yaml:"selector"`
+	Method          string                `json:"method" yaml:"method"`
+	Contracts       []string              `json:"contracts" yaml:"contracts"`
+	Args            []calldataArgOutput   `json:"args" yaml:"args"`
+	WarpPredicates  []warpPredicateOutput `json:"warpPredicates,omitempty" yaml:"warpPredicates,omitempty"`
+}
+
+var decodeCalldataCmd = &cobra.Command{
+	Use:   "decode-calldata [--rpc RPC_URL] TRANSACTION_HASH_OR_CALLDATA",
+	Short: "Decodes the calldata of a Teleporter or cross-chain application transaction",
+	Long: `Given hex encoded calldata, or the hash of a transaction if --rpc is provided,
+this command decodes the calldata against the ABIs of the TeleporterMessenger,
+TeleporterRegistry, ERC20Bridge, NativeTokenSource, NativeTokenDestination and
+ERC20TokenSource contracts, and prints the method called and its arguments.
+For transactions, the signed Warp messages included as predicates in the
+transaction's access list are decoded as well, and the predicate selected by the
+messageIndex argument of calls such as receiveCrossChainMessage is marked.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		input, err := parseHexBytes(args[0])
+		cobra.CheckErr(err)
+
+		var out *calldataOutput
+		if rpcEndpoint != "" && len(input) == common.HashLength {
+			c, err := ethclient.Dial(rpcEndpoint)
+			cobra.CheckErr(err)
+			defer c.Close()
+			tx, _, err := c.TransactionByHash(context.Background(), common.BytesToHash(input))
+			cobra.CheckErr(err)
+			out, err = decodeCalldata(tx.Data(), tx.AccessList())
+			cobra.CheckErr(err)
+			out.TransactionHash = tx.Hash().Hex()
+			if tx.To() != nil {
+				out.To = tx.To().Hex()
+			}
+		} else {
+			out, err = decodeCalldata(input, nil)
+			cobra.CheckErr(err)
+		}
+
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, out))
+			return
+		}
+		logger.Info("Decoded calldata",
+			zap.String("method", out.Method),
+			zap.Strings("contracts", out.Contracts),
+			zap.Any("args", out.Args))
+		for _, p := range out.WarpPredicates {
+			logger.Info("Decoded Warp predicate",
+				zap.Int("index", p.Index),
+				zap.Bool("selected", p.Selected),
+				zap.Any("message", p.Message),
+				zap.String("error", p.Error))
+		}
+		cmd.Println("Decode calldata command ran successfully")
+	},
+}
+
+// decodeCalldata decodes the calldata against the ABIs of calldataContracts, and the Warp messages in the
+// predicates of the access list.
+func decodeCalldata(data []byte, accessList types.AccessList) (*calldataOutput, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata is shorter than a method selector")
+	}
+	out := &calldataOutput{
+		Selector:  hexutil.Encode(data[:4]),
+		Contracts: []string{},
+		Args:      []calldataArgOutput{},
+	}
+	var method *abi.Method
+	for _, contract := range calldataContracts {
+		contractABI, err := contract.metaData.GetAbi()
+		if err != nil {
+			return nil, err
+		}
+		m, err := contractABI.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		if method == nil {
+			method = m
+		}
+		out.Contracts = append(out.Contracts, contract.name)
+	}
+	if method == nil {
+		return nil, fmt.Errorf("method selector %s not found in any known ABI", out.Selector)
+	}
+	out.Method = method.Sig
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack arguments of %s: %w", method.Sig, err)
+	}
+	messageIndex := -1
+	for i, arg := range method.Inputs {
+		out.Args = append(out.Args, calldataArgOutput{
+			Name:  arg.Name,
+			Type:  arg.Type.String(),
+			Value: abiValueOutput(arg.Name, values[i]),
+		})
+		if index, ok := values[i].(uint32); ok && arg.Name == messageIndexArgName {
+			messageIndex = int(index)
+		}
+	}
+
+	out.WarpPredicates = decodeWarpPredicates(accessList)
+	for i := range out.WarpPredicates {
+		out.WarpPredicates[i].Selected = out.WarpPredicates[i].Index == messageIndex
+	}
+	if messageIndex >= 0 && accessList != nil && messageIndex >= len(out.WarpPredicates) {
+		logger.Warn("Message index does not select a Warp predicate of the transaction",
+			zap.Int("messageIndex", messageIndex),
+			zap.Int("numPredicates", len(out.WarpPredicates)))
+	}
+	return out, nil
+}
+
+// decodeWarpPredicates decodes the Warp messages in the access list entries of the Warp precompile, indexed
+// in the order they are verified and read by getVerifiedWarpMessage.
+func decodeWarpPredicates(accessList types.AccessList) []warpPredicateOutput {
+	var predicates []warpPredicateOutput
+	for _, tuple := range accessList {
+		if tuple.Address != warp.ContractAddress {
+			continue
+		}
+		p := warpPredicateOutput{Index: len(predicates)}
+		b, err := predicate.UnpackPredicate(utils.HashSliceToBytes(tuple.StorageKeys))
+		if err == nil {
+			p.Message, err = decodeWarpMessage(b)
+		}
+		if err != nil {
+			p.Error = err.Error()
+		}
+		predicates = append(predicates, p)
+	}
+	return predicates
+}
+
+// abiValueOutput converts a value unpacked from ABI encoded data to the output format: addresses, bytes
+// and hashes are hex encoded, blockchain and message IDs are CB58 encoded, integers are decimal strings,
+// and structs are converted to maps keyed by their ABI field names.
+func abiValueOutput(name string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return bigIntOutput(v)
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		if isIDArgName(name) {
+			return ids.ID(v).String()
+		}
+		return hexutil.Encode(v[:])
+	case string, bool:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(value)
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out = append(out, abiValueOutput(name, rv.Index(i).Interface()))
+		}
+		return out
+	case reflect.Struct:
+		out := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			fieldName := field.Tag.Get("json")
+			if fieldName == "" {
+				fieldName = field.Name
+			}
+			out[fieldName] = abiValueOutput(fieldName, rv.Field(i).Interface())
+		}
+		return out
+	}
+	return fmt.Sprint(value)
+}
+
+// isIDArgName reports whether the bytes32 argument or field is a blockchain or message ID
+func isIDArgName(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, "blockchainid") || strings.HasSuffix(lower, "messageid") ||
+		strings.HasSuffix(lower, "messageids")
+}
+
+func init() {
+	rootCmd.AddCommand(decodeCalldataCmd)
+	decodeCalldataCmd.Flags().StringVar(&rpcEndpoint, "rpc", "",
+		"RPC endpoint to fetch the transaction from, if a transaction hash is provided")
+}
@@ -0,0 +1,165 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/predicate"
+	"github.com/ava-labs/subnet-evm/utils"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCalldataCmd(t *testing.T) {
+	t.Cleanup(func() { outputFormat = tableOutput })
+
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "no args",
+			args: []string{"decode-calldata"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "help",
+			args: []string{"decode-calldata", "--help"},
+			out:  "decodes the calldata against the ABIs of the TeleporterMessenger",
+		},
+		{
+			name: "receive cross chain message",
+			args: []string{
+				"decode-calldata", "--output", jsonOutput,
+				"0xccb5f8090000000000000000000000000000000000000000000000000000000000000002" +
+					"0000000000000000000000000123456789abcdef0123456789abcdef01234567",
+			},
+			out: `"method": "receiveCrossChainMessage(uint32,address)"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestDecodeCalldata(t *testing.T) {
+	rewardAddress := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+	receiveCalldata, err := teleportermessenger.PackReceiveCrossChainMessage(1, rewardAddress)
+	require.NoError(t, err)
+	sendCalldata, err := teleportermessenger.PackSendCrossChainMessage(teleportermessenger.TeleporterMessageInput{
+		DestinationBlockchainID: ids.ID{1, 2, 3, 4},
+		DestinationAddress:      rewardAddress,
+		FeeInfo: teleportermessenger.TeleporterFeeInfo{
+			FeeTokenAddress: common.HexToAddress("0xabcd"),
+			Amount:          big.NewInt(5),
+		},
+		RequiredGasLimit:        big.NewInt(100_000),
+		AllowedRelayerAddresses: []common.Address{},
+		Message:                 []byte{1, 2, 3},
+	})
+	require.NoError(t, err)
+
+	t.Run("send cross chain message", func(t *testing.T) {
+		out, err := decodeCalldata(sendCalldata, nil)
+		require.NoError(t, err)
+		require.Equal(t, "sendCrossChainMessage((bytes32,address,(address,uint256),uint256,address[],bytes))", out.Method)
+		require.Equal(t, []string{"TeleporterMessenger"}, out.Contracts)
+		require.Len(t, out.Args, 1)
+
+		// Check the output of the nested struct through its json encoding
+		b, err := json.Marshal(out.Args[0].Value)
+		require.NoError(t, err)
+		require.JSONEq(t, fmt.Sprintf(`{
+			"destinationBlockchainID": "%s",
+			"destinationAddress": "%s",
+			"feeInfo": {"feeTokenAddress": "%s", "amount": "5"},
+			"requiredGasLimit": "100000",
+			"allowedRelayerAddresses": [],
+			"message": "0x010203"
+		}`, ids.ID{1, 2, 3, 4}, rewardAddress.Hex(), common.HexToAddress("0xabcd").Hex()), string(b))
+		require.Empty(t, out.WarpPredicates)
+	})
+
+	t.Run("receive cross chain message with predicates", func(t *testing.T) {
+		var accessList types.AccessList
+		for _, sourceChainID := range []ids.ID{{1}, {2}} {
+			unsignedMsg := newTestUnsignedWarpMessage(t, sourceChainID)
+			signedMsg, err := avalancheWarp.NewMessage(unsignedMsg, &avalancheWarp.BitSetSignature{
+				Signers:   set.NewBits(0).Bytes(),
+				Signature: [bls.SignatureLen]byte{},
+			})
+			require.NoError(t, err)
+			accessList = append(accessList, types.AccessTuple{
+				Address:     warp.ContractAddress,
+				StorageKeys: utils.BytesToHashSlice(predicate.PackPredicate(signedMsg.Bytes())),
+			})
+		}
+		// Access list entries of other addresses are not predicates
+		accessList = append(accessList,
+			types.AccessTuple{Address: rewardAddress, StorageKeys: []common.Hash{{1}}},
+			types.AccessTuple{Address: warp.ContractAddress, StorageKeys: []common.Hash{{1}}},
+		)
+
+		out, err := decodeCalldata(receiveCalldata, accessList)
+		require.NoError(t, err)
+		require.Equal(t, "receiveCrossChainMessage(uint32,address)", out.Method)
+		require.Equal(t, []calldataArgOutput{
+			{Name: "messageIndex", Type: "uint32", Value: "1"},
+			{Name: "relayerRewardAddress", Type: "address", Value: rewardAddress.Hex()},
+		}, out.Args)
+
+		require.Len(t, out.WarpPredicates, 3)
+		require.False(t, out.WarpPredicates[0].Selected)
+		require.Equal(t, ids.ID{1}.String(), out.WarpPredicates[0].Message.SourceBlockchainID)
+		require.True(t, out.WarpPredicates[1].Selected)
+		require.Equal(t, ids.ID{2}.String(), out.WarpPredicates[1].Message.SourceBlockchainID)
+		require.Equal(t, "1", out.WarpPredicates[1].Message.Message.MessageNonce)
+		require.Equal(t, 2, out.WarpPredicates[2].Index)
+		require.Nil(t, out.WarpPredicates[2].Message)
+		require.NotEmpty(t, out.WarpPredicates[2].Error)
+	})
+
+	t.Run("method defined by several contracts", func(t *testing.T) {
+		calldata, err := hex.DecodeString("8da5cb5b") // owner()
+		require.NoError(t, err)
+		out, err := decodeCalldata(calldata, nil)
+		require.NoError(t, err)
+		require.Equal(t, "owner()", out.Method)
+		require.Equal(t, []string{"ERC20Bridge", "NativeTokenSource", "NativeTokenDestination", "ERC20TokenSource"},
+			out.Contracts)
+	})
+
+	t.Run("unknown selector", func(t *testing.T) {
+		_, err := decodeCalldata([]byte{1, 2, 3, 4}, nil)
+		require.ErrorContains(t, err, "method selector 0x01020304 not found in any known ABI")
+	})
+
+	t.Run("short calldata", func(t *testing.T) {
+		_, err := decodeCalldata([]byte{1, 2}, nil)
+		require.ErrorContains(t, err, "calldata is shorter than a method selector")
+	})
+}
@@ -0,0 +1,339 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultConfigFileName = ".teleporter-cli.yaml"
+	defaultProfileName    = "default"
+
+	// preferWebsocketAnnotation marks commands that connect to a chain's websocket URL instead
+	// of its RPC URL when the chain is selected by name and has a websocket URL configured.
+	preferWebsocketAnnotation = "teleporter-cli/prefer-websocket"
+)
+
+// Environment variable names read by the testnet test network in tests/testnet/network.go.
+// Chain specific variables are prefixed by the chain's alias, such as subnet_a or c_chain.
+const (
+	envTeleporterContractAddress       = "teleporter_contract_address"
+	envTeleporterRegistryAddressSuffix = "_teleporter_registry_address"
+	envSubnetIDSuffix                  = "_subnet_id"
+	envBlockchainIDSuffix              = "_blockchain_id"
+	envRPCURLSuffix                    = "_rpc_url"
+	envWSURLSuffix                     = "_ws_url"
+)
+
+var (
+	configFile      string
+	profileName     string
+	chainName       string
+	sourceChainName string
+	destChainName   string
+)
+
+// chainConfig describes a chain and the Teleporter contracts deployed on it
+type chainConfig struct {
+	RPCURL                    string `json:"rpcURL,omitempty" yaml:"rpcURL,omitempty"`
+	WSURL                     string `json:"wsURL,omitempty" yaml:"wsURL,omitempty"`
+	BlockchainID              string `json:"blockchainID,omitempty" yaml:"blockchainID,omitempty"`
+	SubnetID                  string `json:"subnetID,omitempty" yaml:"subnetID,omitempty"`
+	EVMChainID                uint64 `json:"evmChainID,omitempty" yaml:"evmChainID,omitempty"`
+	TeleporterAddress         string `json:"teleporterAddress,omitempty" yaml:"teleporterAddress,omitempty"`
+	TeleporterRegistryAddress string `json:"teleporterRegistryAddress,omitempty" yaml:"teleporterRegistryAddress,omitempty"`
+}
+
+// profileConfig is a named set of chains, such as those of a local network or of testnet
+type profileConfig struct {
+	// TeleporterAddress is used for the profile's chains that do not set their own
+	TeleporterAddress string                  `json:"teleporterAddress,omitempty" yaml:"teleporterAddress,omitempty"`
+	Chains            map[string]*chainConfig `json:"chains" yaml:"chains"`
+}
+
+type cliConfig struct {
+	Profiles map[string]*profileConfig `json:"profiles" yaml:"profiles"`
+}
+
+// configFilePath returns the path of the config file, ~/.teleporter-cli.yaml unless --config is provided
+func configFilePath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, defaultConfigFileName), nil
+}
+
+// loadConfig reads the config file at path. A missing file is read as an empty config.
+func loadConfig(path string) (*cliConfig, error) {
+	config := &cliConfig{Profiles: make(map[string]*profileConfig)}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]*profileConfig)
+	}
+	return config, nil
+}
+
+func (c *cliConfig) save(path string) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
+
+// chain returns the configuration of the named chain in the profile,
+// with the profile's Teleporter address applied if the chain does not set one.
+func (c *cliConfig) chain(profile string, name string) (chainConfig, error) {
+	p, ok := c.Profiles[profile]
+	if !ok {
+		return chainConfig{}, fmt.Errorf("profile %s not found in config", profile)
+	}
+	chain, ok := p.Chains[name]
+	if !ok {
+		return chainConfig{}, fmt.Errorf("chain %s not found in profile %s", name, profile)
+	}
+	resolved := *chain
+	if resolved.TeleporterAddress == "" {
+		resolved.TeleporterAddress = p.TeleporterAddress
+	}
+	return resolved, nil
+}
+
+// lookupChain loads the config file and returns the configuration of the named chain in the selected profile
+func lookupChain(name string) (chainConfig, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return chainConfig{}, err
+	}
+	config, err := loadConfig(path)
+	if err != nil {
+		return chainConfig{}, err
+	}
+	return config.chain(profileName, name)
+}
+
+// setChainFlags sets the RPC endpoint, Teleporter address and registry flags of the command from the
+// named chain's configuration. Flags that were explicitly provided take precedence over the configuration,
+// and an explicitly provided registry takes precedence over the configured Teleporter address.
+func setChainFlags(cmd *cobra.Command, name string, rpcFlag string, addressFlag string) error {
+	if name == "" {
+		return nil
+	}
+	chain, err := lookupChain(name)
+	if err != nil {
+		return err
+	}
+	rpcURL := chain.RPCURL
+	if cmd.Annotations[preferWebsocketAnnotation] == "true" && chain.WSURL != "" {
+		rpcURL = chain.WSURL
+	}
+	flags := map[string]string{
+		rpcFlag:     rpcURL,
+		addressFlag: chain.TeleporterAddress,
+	}
+	if cmd.Flags().Lookup(registryFlag) != nil {
+		flags[registryFlag] = chain.TeleporterRegistryAddress
+		// The Teleporter address is resolved from the registry unless it is set, so setting it from the
+		// configuration would override the registry
+		if cmd.Flags().Changed(registryFlag) {
+			delete(flags, addressFlag)
+		}
+	}
+	for flag, value := range flags {
+		if flag == "" || value == "" || cmd.Flags().Changed(flag) {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importEnv creates a profile from environment variables using the layout read by the testnet test
+// network, such as subnet_a_rpc_url and teleporter_contract_address. Each prefix of the chain specific
+// variables becomes a chain alias.
+func importEnv(env map[string]string) *profileConfig {
+	profile := &profileConfig{
+		TeleporterAddress: env[envTeleporterContractAddress],
+		Chains:            make(map[string]*chainConfig),
+	}
+	fields := []struct {
+		suffix string
+		set    func(chain *chainConfig, value string)
+	}{
+		{envTeleporterRegistryAddressSuffix, func(c *chainConfig, v string) { c.TeleporterRegistryAddress = v }},
+		{envSubnetIDSuffix, func(c *chainConfig, v string) { c.SubnetID = v }},
+		{envBlockchainIDSuffix, func(c *chainConfig, v string) { c.BlockchainID = v }},
+		{envRPCURLSuffix, func(c *chainConfig, v string) { c.RPCURL = v }},
+		{envWSURLSuffix, func(c *chainConfig, v string) { c.WSURL = v }},
+	}
+	for key, value := range env {
+		for _, field := range fields {
+			alias, ok := strings.CutSuffix(key, field.suffix)
+			if !ok || alias == "" || value == "" {
+				continue
+			}
+			chain, ok := profile.Chains[alias]
+			if !ok {
+				chain = &chainConfig{}
+				profile.Chains[alias] = chain
+			}
+			field.set(chain, value)
+			break
+		}
+	}
+	return profile
+}
+
+// readEnvFile reads KEY=VALUE lines from a dotenv file such as .env.testnet, skipping comments and blank lines
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line in %s: %s", path, line)
+		}
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return env, scanner.Err()
+}
+
+// environ returns the environment variables of the process
+func environ() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manages the profiles of named chains in the config file",
+	Long: `Manages the config file, ~/.teleporter-cli.yaml by default, which holds named
+profiles of chains. Each chain has an alias, and may set its RPC and websocket URLs,
+blockchain and subnet IDs, EVM chain ID, and TeleporterMessenger and
+TeleporterRegistry addresses. Commands that connect to a single chain accept
+--chain NAME instead of --rpc and --teleporter-address, and commands that connect
+to both the source and destination chains of a message accept --source-chain and
+--destination-chain. Chains are looked up in the profile selected by --profile.`,
+	Args: cobra.NoArgs,
+}
+
+var (
+	envFile string
+)
+
+var configImportEnvCmd = &cobra.Command{
+	Use:   "import-env [--env-file FILE]",
+	Short: "Imports a profile from environment variables",
+	Long: `Imports the profile selected by --profile from environment variables using the
+same layout as the testnet test network: teleporter_contract_address, and for each
+chain alias such as subnet_a or c_chain, subnet_a_rpc_url, subnet_a_ws_url,
+subnet_a_blockchain_id, subnet_a_subnet_id and subnet_a_teleporter_registry_address.
+The variables are read from --env-file if provided, such as .env.testnet, and from
+the environment otherwise. An existing profile with the same name is replaced.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		env := environ()
+		if envFile != "" {
+			var err error
+			env, err = readEnvFile(envFile)
+			cobra.CheckErr(err)
+		}
+		profile := importEnv(env)
+		if len(profile.Chains) == 0 {
+			cobra.CheckErr(fmt.Errorf("no chains found in environment variables"))
+		}
+
+		path, err := configFilePath()
+		cobra.CheckErr(err)
+		config, err := loadConfig(path)
+		cobra.CheckErr(err)
+		config.Profiles[profileName] = profile
+		cobra.CheckErr(config.save(path))
+
+		aliases := make([]string, 0, len(profile.Chains))
+		for alias := range profile.Chains {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		logger.Info("Imported profile",
+			zap.String("profile", profileName),
+			zap.String("path", path),
+			zap.Strings("chains", aliases))
+		cmd.Println("Config import-env command ran successfully")
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Prints the config file",
+	Long:  `Prints the profiles in the config file.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := configFilePath()
+		cobra.CheckErr(err)
+		config, err := loadConfig(path)
+		cobra.CheckErr(err)
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, config))
+			return
+		}
+		b, err := yaml.Marshal(config)
+		cobra.CheckErr(err)
+		cmd.Print(string(b))
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "",
+		"Config file holding the profiles of named chains (default ~/"+defaultConfigFileName+")")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", defaultProfileName,
+		"Profile of the config file to look up named chains in")
+
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configImportEnvCmd, configShowCmd)
+	configImportEnvCmd.Flags().StringVar(&envFile, "env-file", "",
+		"Dotenv file to read the environment variables from instead of the environment")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+const testEnvFile = `# Subnet A (Dispatch)
+subnet_a_rpc_url=https://subnets.avax.network/dispatch/testnet/rpc
+subnet_a_subnet_id=7WtoAMPhrmh5KosDUsFL9yTcvw7YSxiKHPpdfs4JsgW47oZT5
+subnet_a_blockchain_id=2D8RG4UpSXbPbvPCAWppNJyqTG2i2CAXSkTgmTBBvs7GKNZjsY
+subnet_a_teleporter_registry_address=0xf86cb19ad8405aefa7d09c778215d2cb6ebfb228
+
+# C-Chain
+export c_chain_rpc_url="https://api.avax-test.network/ext/bc/C/rpc"
+c_chain_ws_url=wss://api.avax-test.network/ext/bc/C/ws
+c_chain_blockchain_id=yH8D7ThNJkxmtkuv2jgBa4P1Rn3Qpr4pPr7QYNfcdoS6k6HWp
+
+# Teleporter Contract
+teleporter_contract_address=0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf
+user_address=
+`
+
+func writeTestFile(t *testing.T, name string, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestImportEnv(t *testing.T) {
+	env, err := readEnvFile(writeTestFile(t, ".env", testEnvFile))
+	require.NoError(t, err)
+	require.Equal(t, "https://api.avax-test.network/ext/bc/C/rpc", env["c_chain_rpc_url"])
+
+	profile := importEnv(env)
+	require.Equal(t, &profileConfig{
+		TeleporterAddress: "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf",
+		Chains: map[string]*chainConfig{
+			"subnet_a": {
+				RPCURL:                    "https://subnets.avax.network/dispatch/testnet/rpc",
+				SubnetID:                  "7WtoAMPhrmh5KosDUsFL9yTcvw7YSxiKHPpdfs4JsgW47oZT5",
+				BlockchainID:              "2D8RG4UpSXbPbvPCAWppNJyqTG2i2CAXSkTgmTBBvs7GKNZjsY",
+				TeleporterRegistryAddress: "0xf86cb19ad8405aefa7d09c778215d2cb6ebfb228",
+			},
+			"c_chain": {
+				RPCURL:       "https://api.avax-test.network/ext/bc/C/rpc",
+				WSURL:        "wss://api.avax-test.network/ext/bc/C/ws",
+				BlockchainID: "yH8D7ThNJkxmtkuv2jgBa4P1Rn3Qpr4pPr7QYNfcdoS6k6HWp",
+			},
+		},
+	}, profile)
+}
+
+func TestCLIConfigChain(t *testing.T) {
+	config := &cliConfig{Profiles: map[string]*profileConfig{
+		"local": {
+			TeleporterAddress: "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf",
+			Chains: map[string]*chainConfig{
+				"a": {RPCURL: "http://127.0.0.1:9650/ext/bc/A/rpc"},
+				"b": {RPCURL: "http://127.0.0.1:9650/ext/bc/B/rpc", TeleporterAddress: "0x01"},
+			},
+		},
+	}}
+
+	chain, err := config.chain("local", "a")
+	require.NoError(t, err)
+	require.Equal(t, "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf", chain.TeleporterAddress)
+	chain, err = config.chain("local", "b")
+	require.NoError(t, err)
+	require.Equal(t, "0x01", chain.TeleporterAddress)
+
+	_, err = config.chain("local", "c")
+	require.ErrorContains(t, err, "chain c not found in profile local")
+	_, err = config.chain("testnet", "a")
+	require.ErrorContains(t, err, "profile testnet not found in config")
+}
+
+func TestSetChainFlags(t *testing.T) {
+	path := writeTestFile(t, "config.yaml", `profiles:
+  default:
+    teleporterAddress: "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf"
+    chains:
+      c_chain:
+        rpcURL: https://api.avax-test.network/ext/bc/C/rpc
+        wsURL: wss://api.avax-test.network/ext/bc/C/ws
+`)
+	configFile, profileName = path, defaultProfileName
+	t.Cleanup(func() { configFile = "" })
+
+	newCmd := func(annotations map[string]string, args ...string) (*cobra.Command, *string, *string) {
+		cmd := &cobra.Command{Annotations: annotations}
+		rpc := cmd.Flags().String("rpc", "", "")
+		address := cmd.Flags().String("teleporter-address", "", "")
+		cmd.Flags().String(registryFlag, "", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd, rpc, address
+	}
+
+	cmd, rpc, address := newCmd(nil)
+	require.NoError(t, setChainFlags(cmd, "c_chain", "rpc", "teleporter-address"))
+	require.Equal(t, "https://api.avax-test.network/ext/bc/C/rpc", *rpc)
+	require.Equal(t, "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf", *address)
+
+	// Explicitly provided flags take precedence
+	cmd, rpc, address = newCmd(nil, "--rpc", "http://127.0.0.1:9650/ext/bc/C/rpc")
+	require.NoError(t, setChainFlags(cmd, "c_chain", "rpc", "teleporter-address"))
+	require.Equal(t, "http://127.0.0.1:9650/ext/bc/C/rpc", *rpc)
+	require.Equal(t, "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf", *address)
+
+	// An explicitly provided registry takes precedence over the configured Teleporter address
+	cmd, _, address = newCmd(nil, "--registry", "0x0123456789abcdef0123456789abcdef01234567")
+	require.NoError(t, setChainFlags(cmd, "c_chain", "rpc", "teleporter-address"))
+	require.Empty(t, *address)
+	require.False(t, cmd.Flags().Changed("teleporter-address"))
+
+	cmd, rpc, _ = newCmd(map[string]string{preferWebsocketAnnotation: "true"})
+	require.NoError(t, setChainFlags(cmd, "c_chain", "rpc", "teleporter-address"))
+	require.Equal(t, "wss://api.avax-test.network/ext/bc/C/ws", *rpc)
+
+	cmd, _, _ = newCmd(nil)
+	require.ErrorContains(t, setChainFlags(cmd, "subnet_a", "rpc", "teleporter-address"), "chain subnet_a not found")
+}
+
+func TestConfigImportEnvCmd(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	envPath := writeTestFile(t, ".env", testEnvFile)
+	t.Cleanup(func() { configFile, envFile, profileName = "", "", defaultProfileName })
+
+	_, err := executeTestCmd(t, rootCmd,
+		"config", "import-env", "--config", configPath, "--profile", "testnet", "--env-file", envPath)
+	require.NoError(t, err)
+
+	config, err := loadConfig(configPath)
+	require.NoError(t, err)
+	chain, err := config.chain("testnet", "subnet_a")
+	require.NoError(t, err)
+	require.Equal(t, "https://subnets.avax.network/dispatch/testnet/rpc", chain.RPCURL)
+	require.Equal(t, "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf", chain.TeleporterAddress)
+}
@@ -0,0 +1,1040 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+const (
+	defaultDashboardLookbackBlocks = 1_000
+
+	// dashboardMaxFailures is the number of recent execution failures kept by the dashboard
+	dashboardMaxFailures = 100
+	// dashboardPageRows is the number of pending messages and execution failures listed per page in the table
+	// output, in addition to the per pair counts
+	dashboardPageRows = 20
+
+	// clearScreen moves the cursor to the top left corner of the terminal and clears the screen
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+var (
+	dashboardChainNames      []string
+	dashboardRelayers        []string
+	dashboardTokens          []string
+	dashboardRefreshInterval time.Duration
+	dashboardLookbackBlocks  uint64
+)
+
+// dashboardChain is a chain shown by the dashboard, with the clients used to query it and watch its logs
+type dashboardChain struct {
+	name              string
+	blockchainID      ids.ID
+	teleporterAddress common.Address
+	registryAddress   common.Address
+	rpcURL            string
+	wsURL             string
+
+	caller bind.ContractCaller
+}
+
+type dashboardPairOutput struct {
+	Source           string `json:"source" yaml:"source"`
+	Destination      string `json:"destination" yaml:"destination"`
+	PendingMessages  int    `json:"pendingMessages" yaml:"pendingMessages"`
+	ReceiptQueueSize string `json:"receiptQueueSize" yaml:"receiptQueueSize"`
+}
+
+type pendingMessageOutput struct {
+	MessageID    string `json:"messageID" yaml:"messageID"`
+	Source       string `json:"source" yaml:"source"`
+	Destination  string `json:"destination" yaml:"destination"`
+	MessageNonce string `json:"messageNonce" yaml:"messageNonce"`
+	BlockNumber  uint64 `json:"blockNumber" yaml:"blockNumber"`
+}
+
+type executionFailureOutput struct {
+	Chain           string `json:"chain" yaml:"chain"`
+	MessageID       string `json:"messageID" yaml:"messageID"`
+	Source          string `json:"source" yaml:"source"`
+	BlockNumber     uint64 `json:"blockNumber" yaml:"blockNumber"`
+	TransactionHash string `json:"transactionHash" yaml:"transactionHash"`
+}
+
+type dashboardRewardOutput struct {
+	Chain   string `json:"chain" yaml:"chain"`
+	Relayer string `json:"relayer" yaml:"relayer"`
+	Token   string `json:"token" yaml:"token"`
+	Amount  string `json:"amount" yaml:"amount"`
+}
+
+type dashboardRegistryOutput struct {
+	Chain         string `json:"chain" yaml:"chain"`
+	Address       string `json:"address" yaml:"address"`
+	LatestVersion string `json:"latestVersion" yaml:"latestVersion"`
+}
+
+type dashboardErrorOutput struct {
+	Chain string `json:"chain" yaml:"chain"`
+	Error string `json:"error" yaml:"error"`
+}
+
+type dashboardOutput struct {
+	UpdatedAt       string                    `json:"updatedAt" yaml:"updatedAt"`
+	Pairs           []dashboardPairOutput     `json:"pairs" yaml:"pairs"`
+	PendingMessages []pendingMessageOutput    `json:"pendingMessages" yaml:"pendingMessages"`
+	Failures        []executionFailureOutput  `json:"failures" yaml:"failures"`
+	Rewards         []dashboardRewardOutput   `json:"rewards" yaml:"rewards"`
+	Registries      []dashboardRegistryOutput `json:"registries" yaml:"registries"`
+	Errors          []dashboardErrorOutput    `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// chainPair identifies messages sent from the source chain to the destination chain
+type chainPair struct {
+	source      ids.ID
+	destination ids.ID
+}
+
+type pendingMessage struct {
+	messageID   ids.ID
+	pair        chainPair
+	nonce       string
+	blockNumber uint64
+}
+
+// dashboardState holds the state shown by the dashboard. Pending messages and execution failures are
+// updated from the logs of each chain as they arrive, while receipt queue sizes, relayer rewards and
+// registry versions are read from the contracts on every refresh.
+type dashboardState struct {
+	lock     sync.Mutex
+	chains   []*dashboardChain
+	names    map[ids.ID]string
+	relayers []common.Address
+	tokens   []common.Address
+
+	pending           map[ids.ID]pendingMessage
+	failures          []executionFailureOutput
+	receiptQueueSizes map[chainPair]*big.Int
+	// Relayer rewards and registry versions by chain name, as of the last successful refresh of the chain
+	rewards    map[string][]dashboardRewardOutput
+	registries map[string]dashboardRegistryOutput
+	// Errors of the last refresh of each chain, cleared once the chain is refreshed successfully
+	errors map[string]string
+	// Errors that stopped the log watcher of each chain. They are not cleared, since the chain's pending
+	// messages and execution failures are no longer updated.
+	watchErrors map[string]string
+	updatedAt   time.Time
+}
+
+func newDashboardState(chains []*dashboardChain, relayers []common.Address, tokens []common.Address) *dashboardState {
+	s := &dashboardState{
+		chains:            chains,
+		names:             make(map[ids.ID]string),
+		relayers:          relayers,
+		tokens:            tokens,
+		pending:           make(map[ids.ID]pendingMessage),
+		receiptQueueSizes: make(map[chainPair]*big.Int),
+		rewards:           make(map[string][]dashboardRewardOutput),
+		registries:        make(map[string]dashboardRegistryOutput),
+		errors:            make(map[string]string),
+		watchErrors:       make(map[string]string),
+	}
+	for _, chain := range chains {
+		s.names[chain.blockchainID] = chain.name
+	}
+	return s
+}
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard [--chains NAME,...] [--relayer ADDRESS --token ADDRESS]",
+	Short: "Shows a live dashboard of the Teleporter state of a set of chains",
+	Long: `Shows a terminal dashboard of the chains of the profile selected by --profile in
+the config file, or of the chains named by --chains. For each pair of chains, the
+dashboard shows the number of messages sent from one chain to the other that have
+not been received yet, and the size of the destination chain's receipt queue for
+the source chain. It also lists the pending messages, the recent message execution
+failures that have not been retried successfully, the reward balances of each
+--relayer in each --token, and the latest version of each chain's
+TeleporterRegistry, if configured. Each chain must have a blockchain ID and a
+Teleporter or TeleporterRegistry address configured.
+
+Messages and failures are tracked from the logs emitted since --lookback-blocks
+before the latest block, received through a subscription if the chain has a
+websocket URL configured, and polled otherwise. The contract state is read again
+every --refresh-interval. In json and yaml output formats, one document is
+printed per refresh instead. Press Ctrl+C to exit.
+
+When run in a terminal, the dashboard is interactive:
+  up/down, k/j      select a chain pair
+  enter             show only the pending messages and failures of the selected
+                    pair, or of all pairs again
+  tab               switch between the pending messages and failures
+  left/right, p/n   page through the pending messages or failures
+  q                 exit`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		relayers, err := parseAddresses(dashboardRelayers)
+		cobra.CheckErr(err)
+		tokens, err := parseAddresses(dashboardTokens)
+		cobra.CheckErr(err)
+		path, err := configFilePath()
+		cobra.CheckErr(err)
+		config, err := loadConfig(path)
+		cobra.CheckErr(err)
+		chains, err := dashboardChains(config, profileName, dashboardChainNames)
+		cobra.CheckErr(err)
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		state := newDashboardState(chains, relayers, tokens)
+		changed := make(chan struct{}, 1)
+		notify := func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer cancel()
+		for _, chain := range chains {
+			chain := chain
+			w, err := newDashboardWatcher(ctx, chain, dashboardLookbackBlocks, func(log types.Log) error {
+				state.handleLog(chain, log)
+				notify()
+				return nil
+			})
+			cobra.CheckErr(err)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := w.run(ctx); err != nil {
+					state.setWatchError(chain.name, err)
+					notify()
+				}
+			}()
+		}
+
+		// In a terminal, keys are read from stdin in raw mode, which must be restored before exiting
+		view := &dashboardView{}
+		var keys <-chan string
+		restore := func() {}
+		checkErr := func(err error) {
+			if err != nil {
+				restore()
+				cobra.CheckErr(err)
+			}
+		}
+		stdin := int(os.Stdin.Fd())
+		if isTableOutput() && isTerminal(cmd.OutOrStdout()) && term.IsTerminal(stdin) {
+			oldState, err := term.MakeRaw(stdin)
+			if err != nil {
+				logger.Warn("Failed to read keys from the terminal", zap.Error(err))
+			} else {
+				restore = func() { _ = term.Restore(stdin, oldState) }
+				defer restore()
+				view.interactive = true
+				keys = readDashboardKeys(os.Stdin)
+			}
+		}
+
+		render := func() {
+			out := state.output()
+			if !isTableOutput() {
+				checkErr(printStreamOutput(cmd, out))
+				return
+			}
+			var b bytes.Buffer
+			checkErr(renderDashboard(&b, out, view))
+			if !isTerminal(cmd.OutOrStdout()) {
+				cmd.Print(b.String())
+				return
+			}
+			screen := b.String()
+			if view.interactive {
+				// Raw mode does not translate newlines to carriage returns and line feeds
+				screen = strings.ReplaceAll(screen, "\n", "\r\n")
+			}
+			cmd.Print(clearScreen + screen)
+		}
+		ticker := time.NewTicker(dashboardRefreshInterval)
+		defer ticker.Stop()
+		state.refresh(ctx)
+		render()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				state.refresh(ctx)
+				render()
+			case <-changed:
+				// Redraw with the new logs without waiting for the next refresh. Machine readable
+				// documents are only printed once per refresh.
+				if isTableOutput() {
+					render()
+				}
+			case key, ok := <-keys:
+				if !ok {
+					keys = nil
+					continue
+				}
+				if view.handleKey(key, state.output()) {
+					return
+				}
+				render()
+			}
+		}
+	},
+}
+
+// dashboardChains returns the named chains of the profile, or all of its chains if no names are given,
+// sorted by name. Chains are connected to later, once their watchers are created.
+func dashboardChains(config *cliConfig, profile string, names []string) ([]*dashboardChain, error) {
+	if len(names) == 0 {
+		p, ok := config.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %s not found in config", profile)
+		}
+		for name := range p.Chains {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no chains found in profile %s", profile)
+	}
+	names = append([]string{}, names...)
+	sort.Strings(names)
+
+	var chains []*dashboardChain
+	for _, name := range names {
+		chain, err := config.chain(profile, name)
+		if err != nil {
+			return nil, err
+		}
+		if chain.RPCURL == "" && chain.WSURL == "" {
+			return nil, fmt.Errorf("chain %s has no RPC URL configured", name)
+		}
+		blockchainID, err := parseID(chain.BlockchainID)
+		if err != nil {
+			return nil, fmt.Errorf("chain %s has no valid blockchain ID configured: %w", name, err)
+		}
+		if chain.TeleporterAddress == "" && chain.TeleporterRegistryAddress == "" {
+			return nil, fmt.Errorf("chain %s has no Teleporter or TeleporterRegistry address configured", name)
+		}
+		teleporterAddress, err := parseAddress(chain.TeleporterAddress)
+		if err != nil {
+			return nil, err
+		}
+		registryAddress, err := parseAddress(chain.TeleporterRegistryAddress)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, &dashboardChain{
+			name:              name,
+			blockchainID:      blockchainID,
+			teleporterAddress: teleporterAddress,
+			registryAddress:   registryAddress,
+			rpcURL:            chain.RPCURL,
+			wsURL:             chain.WSURL,
+		})
+	}
+	return chains, nil
+}
+
+// newDashboardWatcher connects to the chain, resolves its Teleporter address from the registry if it
+// is not configured, and returns a watcher of its TeleporterMessenger logs starting lookbackBlocks
+// before the latest block. The logs are received through a subscription if a websocket URL is configured.
+func newDashboardWatcher(
+	ctx context.Context,
+	chain *dashboardChain,
+	lookbackBlocks uint64,
+	handle func(log types.Log) error,
+) (*logWatcher, error) {
+	rpcURL := chain.rpcURL
+	if rpcURL == "" {
+		rpcURL = chain.wsURL
+	}
+	c, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to chain %s: %w", chain.name, err)
+	}
+	chain.caller = c
+
+	if chain.teleporterAddress == (common.Address{}) {
+		registry, err := newRegistryCaller(chain.registryAddress, c)
+		if err != nil {
+			return nil, err
+		}
+		chain.teleporterAddress, err = resolveTeleporterAddress(ctx, registry, 0)
+		if err != nil {
+			return nil, fmt.Errorf("chain %s: %w", chain.name, err)
+		}
+	}
+
+	latest, err := c.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("chain %s: %w", chain.name, err)
+	}
+	fromBlock := uint64(0)
+	if latest > lookbackBlocks {
+		fromBlock = latest - lookbackBlocks
+	}
+	w := &logWatcher{
+		client:       c,
+		addresses:    []common.Address{chain.teleporterAddress},
+		fromBlock:    fromBlock,
+		pollInterval: dashboardRefreshInterval,
+		handle:       handle,
+	}
+	if chain.wsURL != "" {
+		w.dial = func(ctx context.Context) (logReader, error) {
+			c, err := ethclient.DialContext(ctx, chain.wsURL)
+			if err != nil {
+				return nil, err
+			}
+			return c, nil
+		}
+		w.client, err = w.dial(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to chain %s: %w", chain.name, err)
+		}
+	}
+	return w, nil
+}
+
+// handleLog updates the pending messages and execution failures from a log emitted by the chain's
+// TeleporterMessenger contract. Logs removed by a reorg undo the updates of the original log.
+func (s *dashboardState) handleLog(chain *dashboardChain, log types.Log) {
+	out, err := decodeLogOutput(log)
+	if err != nil {
+		logger.Debug("Failed to decode log",
+			zap.String("chain", chain.name),
+			zap.String("txHash", log.TxHash.Hex()),
+			zap.Error(err))
+		return
+	}
+	if out == nil || out.Event == nil {
+		return
+	}
+	event := out.Event
+	messageID, err := ids.FromString(event.MessageID)
+	if err != nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch event.Name {
+	case teleportermessenger.SendCrossChainMessage.String():
+		destinationBlockchainID, err := ids.FromString(event.DestinationBlockchainID)
+		if err != nil {
+			return
+		}
+		// Only messages between the dashboard's chains are tracked
+		if _, ok := s.names[destinationBlockchainID]; !ok {
+			return
+		}
+		if out.Removed {
+			delete(s.pending, messageID)
+			return
+		}
+		s.pending[messageID] = pendingMessage{
+			messageID:   messageID,
+			pair:        chainPair{source: chain.blockchainID, destination: destinationBlockchainID},
+			nonce:       event.Message.MessageNonce,
+			blockNumber: out.BlockNumber,
+		}
+	case teleportermessenger.ReceiveCrossChainMessage.String():
+		if !out.Removed {
+			delete(s.pending, messageID)
+			return
+		}
+		// The delivery was reorged out, so the message is pending again. The block it was sent in is unknown.
+		sourceBlockchainID, err := ids.FromString(event.SourceBlockchainID)
+		if err != nil {
+			return
+		}
+		if _, ok := s.names[sourceBlockchainID]; !ok {
+			return
+		}
+		s.pending[messageID] = pendingMessage{
+			messageID: messageID,
+			pair:      chainPair{source: sourceBlockchainID, destination: chain.blockchainID},
+			nonce:     event.Message.MessageNonce,
+		}
+	case teleportermessenger.MessageExecutionFailed.String():
+		if out.Removed {
+			s.removeFailure(chain.name, event.MessageID)
+			return
+		}
+		s.failures = append(s.failures, executionFailureOutput{
+			Chain:           chain.name,
+			MessageID:       event.MessageID,
+			Source:          s.chainName(event.SourceBlockchainID),
+			BlockNumber:     out.BlockNumber,
+			TransactionHash: out.TransactionHash,
+		})
+		if len(s.failures) > dashboardMaxFailures {
+			s.failures = s.failures[len(s.failures)-dashboardMaxFailures:]
+		}
+	case teleportermessenger.MessageExecuted.String():
+		// A failed message that executes was retried successfully
+		if !out.Removed {
+			s.removeFailure(chain.name, event.MessageID)
+		}
+	}
+}
+
+func (s *dashboardState) removeFailure(chain string, messageID string) {
+	failures := s.failures[:0]
+	for _, f := range s.failures {
+		if f.Chain != chain || f.MessageID != messageID {
+			failures = append(failures, f)
+		}
+	}
+	s.failures = failures
+}
+
+// chainName returns the name of the chain with the CB58 encoded blockchain ID, or the ID for unknown chains
+func (s *dashboardState) chainName(blockchainID string) string {
+	id, err := ids.FromString(blockchainID)
+	if err != nil {
+		return blockchainID
+	}
+	if name, ok := s.names[id]; ok {
+		return name
+	}
+	return blockchainID
+}
+
+func (s *dashboardState) setError(chain string, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err == nil {
+		delete(s.errors, chain)
+		return
+	}
+	s.errors[chain] = err.Error()
+}
+
+// setWatchError records the error that stopped the log watcher of the chain
+func (s *dashboardState) setWatchError(chain string, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.watchErrors[chain] = err.Error()
+}
+
+// refresh reads the receipt queue sizes, relayer rewards and registry versions of each chain, and removes
+// the pending messages that have been received. Chains that fail to be read keep the receipt queue sizes,
+// rewards and registry version of their last successful read, and their error is shown until they are
+// read successfully.
+func (s *dashboardState) refresh(ctx context.Context) {
+	s.lock.Lock()
+	pending := make([]pendingMessage, 0, len(s.pending))
+	for _, m := range s.pending {
+		pending = append(pending, m)
+	}
+	s.lock.Unlock()
+
+	for _, chain := range s.chains {
+		if chain.caller == nil {
+			continue
+		}
+		err := s.refreshChain(ctx, chain, pending)
+		s.setError(chain.name, err)
+		if err != nil {
+			logger.Debug("Failed to refresh chain", zap.String("chain", chain.name), zap.Error(err))
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.updatedAt = time.Now()
+}
+
+// refreshChain reads the contract state of the chain, and updates the dashboard's state once it has all been
+// read successfully
+func (s *dashboardState) refreshChain(ctx context.Context, chain *dashboardChain, pending []pendingMessage) error {
+	teleporter, err := teleportermessenger.NewTeleporterMessengerCaller(chain.teleporterAddress, chain.caller)
+	if err != nil {
+		return err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	receiptQueueSizes := make(map[chainPair]*big.Int)
+	for _, source := range s.chains {
+		if source == chain {
+			continue
+		}
+		size, err := teleporter.GetReceiptQueueSize(opts, source.blockchainID)
+		if err != nil {
+			return err
+		}
+		receiptQueueSizes[chainPair{source: source.blockchainID, destination: chain.blockchainID}] = size
+	}
+
+	var received []ids.ID
+	for _, m := range pending {
+		if m.pair.destination != chain.blockchainID {
+			continue
+		}
+		ok, err := teleporter.MessageReceived(opts, m.messageID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			received = append(received, m.messageID)
+		}
+	}
+
+	var rewards []dashboardRewardOutput
+	for _, relayer := range s.relayers {
+		for _, token := range s.tokens {
+			amount, err := teleporter.CheckRelayerRewardAmount(opts, relayer, token)
+			if err != nil {
+				return err
+			}
+			rewards = append(rewards, dashboardRewardOutput{
+				Chain:   chain.name,
+				Relayer: relayer.Hex(),
+				Token:   token.Hex(),
+				Amount:  bigIntOutput(amount),
+			})
+		}
+	}
+
+	var registry *dashboardRegistryOutput
+	if chain.registryAddress != (common.Address{}) {
+		caller, err := newRegistryCaller(chain.registryAddress, chain.caller)
+		if err != nil {
+			return err
+		}
+		version, err := caller.LatestVersion(opts)
+		if err != nil {
+			return fmt.Errorf("failed to get latest version from registry: %w", err)
+		}
+		registry = &dashboardRegistryOutput{
+			Chain:         chain.name,
+			Address:       chain.registryAddress.Hex(),
+			LatestVersion: bigIntOutput(version),
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for pair, size := range receiptQueueSizes {
+		s.receiptQueueSizes[pair] = size
+	}
+	for _, messageID := range received {
+		delete(s.pending, messageID)
+	}
+	s.rewards[chain.name] = rewards
+	if registry != nil {
+		s.registries[chain.name] = *registry
+	}
+	return nil
+}
+
+// output returns a snapshot of the dashboard's state
+func (s *dashboardState) output() *dashboardOutput {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	out := &dashboardOutput{
+		Pairs:           []dashboardPairOutput{},
+		PendingMessages: []pendingMessageOutput{},
+		Failures:        append([]executionFailureOutput{}, s.failures...),
+		Rewards:         []dashboardRewardOutput{},
+		Registries:      []dashboardRegistryOutput{},
+	}
+	for _, chain := range s.chains {
+		out.Rewards = append(out.Rewards, s.rewards[chain.name]...)
+		if registry, ok := s.registries[chain.name]; ok {
+			out.Registries = append(out.Registries, registry)
+		}
+	}
+	if !s.updatedAt.IsZero() {
+		out.UpdatedAt = s.updatedAt.UTC().Format(time.RFC3339)
+	}
+
+	pendingCounts := make(map[chainPair]int)
+	for _, m := range s.pending {
+		pendingCounts[m.pair]++
+		out.PendingMessages = append(out.PendingMessages, pendingMessageOutput{
+			MessageID:    m.messageID.String(),
+			Source:       s.names[m.pair.source],
+			Destination:  s.names[m.pair.destination],
+			MessageNonce: m.nonce,
+			BlockNumber:  m.blockNumber,
+		})
+	}
+	// List the oldest pending messages first
+	sort.Slice(out.PendingMessages, func(i, j int) bool {
+		a, b := out.PendingMessages[i], out.PendingMessages[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.BlockNumber < b.BlockNumber
+	})
+
+	for _, source := range s.chains {
+		for _, destination := range s.chains {
+			if source == destination {
+				continue
+			}
+			pair := chainPair{source: source.blockchainID, destination: destination.blockchainID}
+			out.Pairs = append(out.Pairs, dashboardPairOutput{
+				Source:           source.name,
+				Destination:      destination.name,
+				PendingMessages:  pendingCounts[pair],
+				ReceiptQueueSize: bigIntOutput(s.receiptQueueSizes[pair]),
+			})
+		}
+	}
+
+	for _, chain := range s.chains {
+		if err, ok := s.errors[chain.name]; ok {
+			out.Errors = append(out.Errors, dashboardErrorOutput{Chain: chain.name, Error: err})
+		}
+		if err, ok := s.watchErrors[chain.name]; ok {
+			out.Errors = append(out.Errors, dashboardErrorOutput{
+				Chain: chain.name,
+				Error: "log watcher stopped: " + err,
+			})
+		}
+	}
+	return out
+}
+
+// dashboardSection is a section of the dashboard that is paged through
+type dashboardSection int
+
+const (
+	pendingSection dashboardSection = iota
+	failuresSection
+)
+
+// dashboardView is the part of the dashboard shown, as selected with keys when the dashboard is interactive.
+// The pending messages and failures are shown for all chain pairs, or only for the selected pair if filter is
+// set, one page at a time.
+type dashboardView struct {
+	interactive bool
+	selected    int
+	filter      bool
+	focus       dashboardSection
+	pages       [2]int
+}
+
+// handleKey updates the view for the key, and returns true if the key exits the dashboard
+func (v *dashboardView) handleKey(key string, out *dashboardOutput) bool {
+	switch key {
+	case "q", "ctrl+c":
+		return true
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+			v.pages = [2]int{}
+		}
+	case "down", "j":
+		if v.selected < len(out.Pairs)-1 {
+			v.selected++
+			v.pages = [2]int{}
+		}
+	case "enter":
+		v.filter = !v.filter
+		v.pages = [2]int{}
+	case "tab":
+		v.focus = 1 - v.focus
+	case "left", "p":
+		if v.pages[v.focus] > 0 {
+			v.pages[v.focus]--
+		}
+	case "right", "n":
+		if v.pages[v.focus] < numPages(v.sectionLen(out, v.focus))-1 {
+			v.pages[v.focus]++
+		}
+	}
+	return false
+}
+
+// selectedPair returns the chain pair the pending messages and failures are shown for, or nil for all pairs
+func (v *dashboardView) selectedPair(out *dashboardOutput) *dashboardPairOutput {
+	if !v.filter || v.selected >= len(out.Pairs) {
+		return nil
+	}
+	return &out.Pairs[v.selected]
+}
+
+// pendingMessages returns the pending messages of the selected pair, or of all pairs
+func (v *dashboardView) pendingMessages(out *dashboardOutput) []pendingMessageOutput {
+	pair := v.selectedPair(out)
+	if pair == nil {
+		return out.PendingMessages
+	}
+	var messages []pendingMessageOutput
+	for _, m := range out.PendingMessages {
+		if m.Source == pair.Source && m.Destination == pair.Destination {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+// failures returns the execution failures of messages of the selected pair, or of all pairs
+func (v *dashboardView) failures(out *dashboardOutput) []executionFailureOutput {
+	pair := v.selectedPair(out)
+	if pair == nil {
+		return out.Failures
+	}
+	var failures []executionFailureOutput
+	for _, f := range out.Failures {
+		if f.Source == pair.Source && f.Chain == pair.Destination {
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
+
+func (v *dashboardView) sectionLen(out *dashboardOutput, section dashboardSection) int {
+	if section == pendingSection {
+		return len(v.pendingMessages(out))
+	}
+	return len(v.failures(out))
+}
+
+// page returns the bounds of the rows of the section shown, and the number of the page shown. The page is
+// clamped to the last page, since rows may have been removed since it was selected.
+func (v *dashboardView) page(section dashboardSection, rows int) (int, int, int) {
+	page := v.pages[section]
+	if last := numPages(rows) - 1; page > last {
+		page = last
+	}
+	start := page * dashboardPageRows
+	end := start + dashboardPageRows
+	if end > rows {
+		end = rows
+	}
+	return start, end, page
+}
+
+// numPages returns the number of pages of the rows, at least one
+func numPages(rows int) int {
+	if rows == 0 {
+		return 1
+	}
+	return (rows + dashboardPageRows - 1) / dashboardPageRows
+}
+
+// sectionTitle returns the title of a paged section, marking the section keys page through
+func (v *dashboardView) sectionTitle(out *dashboardOutput, section dashboardSection, title string, rows int) string {
+	if pair := v.selectedPair(out); pair != nil {
+		title = fmt.Sprintf("%s FROM %s TO %s", title, pair.Source, pair.Destination)
+	}
+	title = fmt.Sprintf("%s (%d)", title, rows)
+	if !v.interactive {
+		return title
+	}
+	_, _, page := v.page(section, rows)
+	title = fmt.Sprintf("%s, page %d/%d", title, page+1, numPages(rows))
+	if v.focus == section {
+		return "> " + title
+	}
+	return title
+}
+
+// renderDashboard writes the dashboard as a set of tables, with the part selected by the view
+func renderDashboard(w io.Writer, out *dashboardOutput, view *dashboardView) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	updatedAt := out.UpdatedAt
+	if updatedAt == "" {
+		updatedAt = "never"
+	}
+	fmt.Fprintf(tw, "Teleporter dashboard, updated %s\n", updatedAt)
+
+	fmt.Fprintln(tw, "\nCHAIN PAIRS")
+	marker := func(bool) string { return "" }
+	if view.interactive {
+		marker = func(selected bool) string {
+			if selected {
+				return ">\t"
+			}
+			return "\t"
+		}
+		fmt.Fprint(tw, marker(false))
+	}
+	fmt.Fprintln(tw, "SOURCE\tDESTINATION\tPENDING\tRECEIPT QUEUE")
+	for i, p := range out.Pairs {
+		fmt.Fprintf(tw, "%s%s\t%s\t%d\t%s\n",
+			marker(i == view.selected), p.Source, p.Destination, p.PendingMessages, p.ReceiptQueueSize)
+	}
+
+	pending := view.pendingMessages(out)
+	fmt.Fprintf(tw, "\n%s\n", view.sectionTitle(out, pendingSection, "PENDING MESSAGES", len(pending)))
+	fmt.Fprintln(tw, "SOURCE\tDESTINATION\tNONCE\tBLOCK\tMESSAGE ID")
+	start, end, _ := view.page(pendingSection, len(pending))
+	for _, m := range pending[start:end] {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", m.Source, m.Destination, m.MessageNonce, m.BlockNumber, m.MessageID)
+	}
+	if !view.interactive && end < len(pending) {
+		fmt.Fprintf(tw, "... %d more\n", len(pending)-end)
+	}
+
+	failures := view.failures(out)
+	fmt.Fprintf(tw, "\n%s\n", view.sectionTitle(out, failuresSection, "RECENT EXECUTION FAILURES", len(failures)))
+	fmt.Fprintln(tw, "CHAIN\tSOURCE\tBLOCK\tMESSAGE ID\tTRANSACTION")
+	start, end, _ = view.page(failuresSection, len(failures))
+	for _, f := range failures[start:end] {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", f.Chain, f.Source, f.BlockNumber, f.MessageID, f.TransactionHash)
+	}
+	if !view.interactive && end < len(failures) {
+		fmt.Fprintf(tw, "... %d more\n", len(failures)-end)
+	}
+
+	if len(out.Rewards) > 0 {
+		fmt.Fprintln(tw, "\nRELAYER REWARDS")
+		fmt.Fprintln(tw, "CHAIN\tRELAYER\tTOKEN\tAMOUNT")
+		for _, r := range out.Rewards {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Chain, r.Relayer, r.Token, r.Amount)
+		}
+	}
+
+	if len(out.Registries) > 0 {
+		fmt.Fprintln(tw, "\nREGISTRIES")
+		fmt.Fprintln(tw, "CHAIN\tADDRESS\tLATEST VERSION")
+		for _, r := range out.Registries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Chain, r.Address, r.LatestVersion)
+		}
+	}
+
+	if len(out.Errors) > 0 {
+		fmt.Fprintln(tw, "\nERRORS")
+		for _, e := range out.Errors {
+			fmt.Fprintf(tw, "%s\t%s\n", e.Chain, e.Error)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if view.interactive {
+		_, err := fmt.Fprintln(w, "\nup/down select pair, enter filter by pair, tab switch section, left/right page, q quit")
+		return err
+	}
+	return nil
+}
+
+// readDashboardKeys reads the keys pressed in the terminal in raw mode, until reading fails
+func readDashboardKeys(r io.Reader) <-chan string {
+	keys := make(chan string)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 64)
+		for {
+			n, err := r.Read(buf)
+			for _, key := range parseDashboardKeys(buf[:n]) {
+				keys <- key
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// dashboardEscapeKeys are the escape sequences of the special keys used by the dashboard
+var dashboardEscapeKeys = map[string]string{
+	"\x1b[A":  "up",
+	"\x1b[B":  "down",
+	"\x1b[C":  "right",
+	"\x1b[D":  "left",
+	"\x1b[5~": "left",
+	"\x1b[6~": "right",
+}
+
+// parseDashboardKeys splits the bytes read from a terminal in raw mode into the names of the keys pressed.
+// Unknown escape sequences are dropped.
+func parseDashboardKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+		case 0x1b:
+			// An escape sequence ends with a letter or a tilde
+			end := 1
+			if len(b) > 1 && b[1] == '[' {
+				end = 2
+				for end < len(b) && !isEscapeSequenceEnd(b[end]) {
+					end++
+				}
+				if end < len(b) {
+					end++
+				}
+			}
+			if key, ok := dashboardEscapeKeys[string(b[:end])]; ok {
+				keys = append(keys, key)
+			}
+			b = b[end:]
+			continue
+		default:
+			keys = append(keys, string(b[:1]))
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+func isEscapeSequenceEnd(c byte) bool {
+	return c == '~' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// isTerminal returns true if w is a terminal, in which case the screen is cleared before each redraw
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+	dashboardCmd.Flags().StringSliceVar(&dashboardChainNames, "chains", nil,
+		"Names of the chains in the config file to show (default all chains of the profile)")
+	dashboardCmd.Flags().StringSliceVar(&dashboardRelayers, "relayer", nil,
+		"Relayer reward addresses to show the reward balances of")
+	dashboardCmd.Flags().StringSliceVar(&dashboardTokens, "token", nil,
+		"Fee token addresses to show the relayer reward balances in")
+	dashboardCmd.Flags().DurationVar(&dashboardRefreshInterval, "refresh-interval", defaultPollInterval,
+		"Interval between reads of the contract state, and between polls when log subscriptions are unavailable")
+	dashboardCmd.Flags().Uint64Var(&dashboardLookbackBlocks, "lookback-blocks", defaultDashboardLookbackBlocks,
+		"Number of blocks before the latest block to track pending messages and execution failures from")
+}
@@ -0,0 +1,363 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "args",
+			args: []string{"dashboard", "arg"},
+			err:  fmt.Errorf("unknown command \"arg\" for \"teleporter-cli dashboard\""),
+		},
+		{
+			name: "help",
+			args: []string{"dashboard", "--help"},
+			out:  "Shows a terminal dashboard of the chains of the profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestDashboardChains(t *testing.T) {
+	chainA, chainB := ids.GenerateTestID(), ids.GenerateTestID()
+	config := &cliConfig{Profiles: map[string]*profileConfig{
+		defaultProfileName: {
+			TeleporterAddress: "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf",
+			Chains: map[string]*chainConfig{
+				"b": {RPCURL: "http://b", BlockchainID: chainB.String()},
+				"a": {RPCURL: "http://a", WSURL: "ws://a", BlockchainID: chainA.String()},
+			},
+		},
+		"incomplete": {
+			Chains: map[string]*chainConfig{
+				"no-id":      {RPCURL: "http://c", TeleporterAddress: "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf"},
+				"no-address": {RPCURL: "http://d", BlockchainID: chainA.String()},
+			},
+		},
+	}}
+
+	var tests = []struct {
+		name    string
+		profile string
+		names   []string
+		chains  []string
+		err     string
+	}{
+		{
+			name:    "all chains",
+			profile: defaultProfileName,
+			chains:  []string{"a", "b"},
+		},
+		{
+			name:    "named chains",
+			profile: defaultProfileName,
+			names:   []string{"b"},
+			chains:  []string{"b"},
+		},
+		{
+			name:    "unknown chain",
+			profile: defaultProfileName,
+			names:   []string{"c"},
+			err:     "chain c not found in profile default",
+		},
+		{
+			name:    "unknown profile",
+			profile: "unknown",
+			err:     "profile unknown not found in config",
+		},
+		{
+			name:    "missing blockchain ID",
+			profile: "incomplete",
+			names:   []string{"no-id"},
+			err:     "chain no-id has no valid blockchain ID configured",
+		},
+		{
+			name:    "missing address",
+			profile: "incomplete",
+			names:   []string{"no-address"},
+			err:     "chain no-address has no Teleporter or TeleporterRegistry address configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chains, err := dashboardChains(config, tt.profile, tt.names)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, chain := range chains {
+				names = append(names, chain.name)
+				require.Equal(t, common.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf"), chain.teleporterAddress)
+			}
+			require.Equal(t, tt.chains, names)
+		})
+	}
+}
+
+// newTestEventLog returns a log of the Teleporter event with the indexed topics and non-indexed values
+func newTestEventLog(
+	t *testing.T,
+	name string,
+	blockNumber uint64,
+	topics []common.Hash,
+	values ...interface{},
+) types.Log {
+	event := teleporterABI.Events[name]
+	data, err := event.Inputs.NonIndexed().Pack(values...)
+	require.NoError(t, err)
+	return types.Log{
+		Topics:      append([]common.Hash{event.ID}, topics...),
+		Data:        data,
+		BlockNumber: blockNumber,
+		TxHash:      common.BigToHash(common.Big1),
+	}
+}
+
+func TestDashboardStateHandleLog(t *testing.T) {
+	chainA := &dashboardChain{name: "a", blockchainID: ids.GenerateTestID()}
+	chainB := &dashboardChain{name: "b", blockchainID: ids.GenerateTestID()}
+	state := newDashboardState([]*dashboardChain{chainA, chainB}, nil, nil)
+
+	messageID := common.Hash(ids.GenerateTestID())
+	message := createTestTeleporterMessage()
+	message.DestinationBlockchainID = chainB.blockchainID
+	sent := newTestEventLog(t, "SendCrossChainMessage", 10,
+		[]common.Hash{messageID, common.Hash(chainB.blockchainID)},
+		message, teleportermessenger.TeleporterFeeInfo{Amount: common.Big0})
+	received := newTestEventLog(t, "ReceiveCrossChainMessage", 20,
+		[]common.Hash{messageID, common.Hash(chainA.blockchainID), common.HexToHash("0x1")},
+		common.HexToAddress("0x2"), message)
+	failed := newTestEventLog(t, "MessageExecutionFailed", 20,
+		[]common.Hash{messageID, common.Hash(chainA.blockchainID)}, message)
+	executed := newTestEventLog(t, "MessageExecuted", 30,
+		[]common.Hash{messageID, common.Hash(chainA.blockchainID)})
+	removed := func(log types.Log) types.Log {
+		log.Removed = true
+		return log
+	}
+
+	state.handleLog(chainA, sent)
+	out := state.output()
+	require.Len(t, out.PendingMessages, 1)
+	require.Equal(t, pendingMessageOutput{
+		MessageID:    ids.ID(messageID).String(),
+		Source:       "a",
+		Destination:  "b",
+		MessageNonce: message.MessageNonce.String(),
+		BlockNumber:  10,
+	}, out.PendingMessages[0])
+	require.Equal(t, []dashboardPairOutput{
+		{Source: "a", Destination: "b", PendingMessages: 1, ReceiptQueueSize: "0"},
+		{Source: "b", Destination: "a", PendingMessages: 0, ReceiptQueueSize: "0"},
+	}, out.Pairs)
+
+	// The message is delivered and fails to execute
+	state.handleLog(chainB, received)
+	state.handleLog(chainB, failed)
+	out = state.output()
+	require.Empty(t, out.PendingMessages)
+	require.Equal(t, []executionFailureOutput{{
+		Chain:           "b",
+		MessageID:       ids.ID(messageID).String(),
+		Source:          "a",
+		BlockNumber:     20,
+		TransactionHash: failed.TxHash.Hex(),
+	}}, out.Failures)
+
+	// The delivery is reorged out
+	state.handleLog(chainB, removed(failed))
+	state.handleLog(chainB, removed(received))
+	out = state.output()
+	require.Empty(t, out.Failures)
+	require.Len(t, out.PendingMessages, 1)
+	require.Equal(t, "a", out.PendingMessages[0].Source)
+
+	// The message is delivered again, and retried successfully
+	state.handleLog(chainB, received)
+	state.handleLog(chainB, failed)
+	state.handleLog(chainB, executed)
+	out = state.output()
+	require.Empty(t, out.PendingMessages)
+	require.Empty(t, out.Failures)
+
+	// Messages to chains that are not shown are not tracked
+	other := newTestEventLog(t, "SendCrossChainMessage", 40,
+		[]common.Hash{common.Hash(ids.GenerateTestID()), common.Hash(ids.GenerateTestID())},
+		message, teleportermessenger.TeleporterFeeInfo{Amount: common.Big0})
+	state.handleLog(chainA, other)
+	require.Empty(t, state.output().PendingMessages)
+}
+
+func TestDashboardStateRefresh(t *testing.T) {
+	ctx := context.Background()
+	backend, key := newTestBackend(t)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, simulatedChainID)
+	require.NoError(t, err)
+	opts.Context = ctx
+
+	teleporterAddress, tx, _, err := teleportermessenger.DeployTeleporterMessenger(opts, backend)
+	require.NoError(t, err)
+	_, err = waitForSuccess(ctx, backend, tx)
+	require.NoError(t, err)
+
+	chainA := &dashboardChain{
+		name:              "a",
+		blockchainID:      ids.GenerateTestID(),
+		teleporterAddress: teleporterAddress,
+		caller:            backend,
+	}
+	chainB := &dashboardChain{name: "b", blockchainID: ids.GenerateTestID()}
+	relayer, token := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	state := newDashboardState([]*dashboardChain{chainA, chainB}, []common.Address{relayer}, []common.Address{token})
+
+	// A message to chain a that has not been received by its TeleporterMessenger contract
+	messageID := ids.GenerateTestID()
+	state.pending[messageID] = pendingMessage{
+		messageID: messageID,
+		pair:      chainPair{source: chainB.blockchainID, destination: chainA.blockchainID},
+		nonce:     "1",
+	}
+
+	state.refresh(ctx)
+	out := state.output()
+	require.NotEmpty(t, out.UpdatedAt)
+	require.Len(t, out.PendingMessages, 1)
+	require.Equal(t, []dashboardRewardOutput{
+		{Chain: "a", Relayer: relayer.Hex(), Token: token.Hex(), Amount: "0"},
+	}, out.Rewards)
+	require.Empty(t, out.Registries)
+	require.Empty(t, out.Errors)
+
+	var b bytes.Buffer
+	require.NoError(t, renderDashboard(&b, out, &dashboardView{}))
+	require.Contains(t, b.String(), "PENDING MESSAGES (1)")
+	require.Contains(t, b.String(), messageID.String())
+	require.Contains(t, b.String(), "RELAYER REWARDS")
+	require.NotContains(t, b.String(), "REGISTRIES")
+
+	// A chain whose contract state fails to be read shows its error, and keeps its last rewards
+	chainA.teleporterAddress = common.HexToAddress("0x3")
+	state.refresh(ctx)
+	out = state.output()
+	require.Len(t, out.Errors, 1)
+	require.Equal(t, "a", out.Errors[0].Chain)
+	require.Len(t, out.Rewards, 1)
+
+	// The error that stopped the watcher of a chain is shown after the chain is refreshed successfully
+	chainA.teleporterAddress = teleporterAddress
+	state.setWatchError("a", fmt.Errorf("watcher failed"))
+	state.refresh(ctx)
+	require.Equal(t, []dashboardErrorOutput{
+		{Chain: "a", Error: "log watcher stopped: watcher failed"},
+	}, state.output().Errors)
+}
+
+func TestDashboardView(t *testing.T) {
+	out := &dashboardOutput{
+		Pairs: []dashboardPairOutput{
+			{Source: "a", Destination: "b", PendingMessages: dashboardPageRows + 1},
+			{Source: "b", Destination: "a", PendingMessages: 1},
+		},
+		Failures: []executionFailureOutput{{Chain: "a", Source: "b", MessageID: "failed"}},
+	}
+	for i := 0; i < dashboardPageRows+1; i++ {
+		out.PendingMessages = append(out.PendingMessages, pendingMessageOutput{
+			Source:      "a",
+			Destination: "b",
+			MessageID:   fmt.Sprintf("a-to-b-%d", i),
+		})
+	}
+	out.PendingMessages = append(out.PendingMessages, pendingMessageOutput{
+		Source:      "b",
+		Destination: "a",
+		MessageID:   "b-to-a",
+	})
+	render := func(view *dashboardView) string {
+		var b bytes.Buffer
+		require.NoError(t, renderDashboard(&b, out, view))
+		return b.String()
+	}
+
+	// The non-interactive dashboard shows the first page of all pairs
+	screen := render(&dashboardView{})
+	require.Contains(t, screen, "PENDING MESSAGES (22)\n")
+	require.Contains(t, screen, "... 2 more")
+	require.NotContains(t, screen, "b-to-a")
+
+	view := &dashboardView{interactive: true}
+	screen = render(view)
+	require.Contains(t, screen, "> PENDING MESSAGES (22), page 1/2")
+	require.Contains(t, screen, "RECENT EXECUTION FAILURES (1), page 1/1")
+	require.NotContains(t, screen, "... 2 more")
+
+	// Page through the pending messages, past the last page
+	require.False(t, view.handleKey("n", out))
+	require.False(t, view.handleKey("right", out))
+	screen = render(view)
+	require.Contains(t, screen, "page 2/2")
+	require.Contains(t, screen, "b-to-a")
+	require.NotContains(t, screen, "a-to-b-0\n")
+
+	// Select the second pair and filter by it
+	require.False(t, view.handleKey("down", out))
+	require.False(t, view.handleKey("down", out))
+	require.Equal(t, 1, view.selected)
+	require.False(t, view.handleKey("enter", out))
+	screen = render(view)
+	require.Contains(t, screen, "> PENDING MESSAGES FROM b TO a (1), page 1/1")
+	require.Contains(t, screen, "RECENT EXECUTION FAILURES FROM b TO a (1)")
+	require.NotContains(t, screen, "a-to-b")
+
+	// The focus switches to the failures, which do not include failures of other pairs
+	require.False(t, view.handleKey("tab", out))
+	require.False(t, view.handleKey("k", out))
+	screen = render(view)
+	require.Contains(t, screen, "> RECENT EXECUTION FAILURES FROM a TO b (0), page 1/1")
+	require.NotContains(t, screen, "failed")
+
+	require.True(t, view.handleKey("q", out))
+	require.True(t, view.handleKey("ctrl+c", out))
+}
+
+func TestParseDashboardKeys(t *testing.T) {
+	require.Equal(t,
+		[]string{"up", "down", "right", "left", "left", "right", "enter", "enter", "tab", "ctrl+c", "q"},
+		parseDashboardKeys([]byte("\x1b[A\x1b[B\x1b[C\x1b[D\x1b[5~\x1b[6~\r\n\t\x03q")))
+	// Unknown and incomplete escape sequences are dropped
+	require.Equal(t, []string{"j"}, parseDashboardKeys([]byte("\x1b[1;5Aj\x1b")))
+	require.Empty(t, parseDashboardKeys([]byte("\x1b[")))
+}
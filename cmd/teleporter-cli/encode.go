@@ -0,0 +1,321 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+// messageFlags holds the flags describing a TeleporterMessage or TeleporterMessageInput.
+// If file is set, the fields present in the JSON file, which uses the same schema as the
+// json output format, override the flags.
+type messageFlags struct {
+	file                    string
+	messageNonce            string
+	originSenderAddress     string
+	destinationBlockchainID string
+	destinationAddress      string
+	feeTokenAddress         string
+	feeAmount               string
+	requiredGasLimit        string
+	allowedRelayerAddresses []string
+	receipts                []string
+	payload                 string
+	payloadFile             string
+}
+
+type encodedOutput struct {
+	Data string `json:"data" yaml:"data"`
+}
+
+var (
+	encodeMessageFlags    messageFlags
+	encodeInputFlags      messageFlags
+	sourceBlockchainIDArg string
+	messageIndex          uint32
+	relayerRewardAddress  string
+)
+
+var encodeCmd = &cobra.Command{
+	Use:   "encode",
+	Short: "Encodes Teleporter messages and Teleporter contract calldata",
+	Long: `Encodes a Teleporter message or the calldata of a Teleporter contract call from
+either flags or a JSON file. The JSON file uses the same schema as the json output
+format of the decoding commands. The encoded bytes are decoded again and compared
+against the input before being printed, to verify the encoding round trips.`,
+	Args: cobra.NoArgs,
+}
+
+var encodeMessageCmd = &cobra.Command{
+	Use:   "message [--file FILE]",
+	Short: "Encodes a TeleporterMessage struct into its ABI encoded bytes",
+	Long: `Encodes a TeleporterMessage struct into the ABI encoded bytes included in the
+Warp message payload, which can be decoded with the message command.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		message, err := encodeMessageFlags.teleporterMessage()
+		cobra.CheckErr(err)
+
+		b, err := teleportermessenger.PackTeleporterMessage(message)
+		cobra.CheckErr(err)
+
+		unpacked, err := teleportermessenger.UnpackTeleporterMessage(b)
+		cobra.CheckErr(err)
+		cobra.CheckErr(verifyRoundTrip(
+			newTeleporterMessageOutput(message), newTeleporterMessageOutput(*unpacked)))
+
+		cobra.CheckErr(printEncoded(cmd, b))
+	},
+}
+
+var encodeSendCmd = &cobra.Command{
+	Use:   "send [--file FILE]",
+	Short: "Encodes the calldata of a sendCrossChainMessage call",
+	Long: `Encodes a TeleporterMessageInput struct into the calldata of a call to the
+sendCrossChainMessage function of the TeleporterMessenger contract.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, err := encodeInputFlags.teleporterMessageInput()
+		cobra.CheckErr(err)
+
+		b, err := teleportermessenger.PackSendCrossChainMessage(input)
+		cobra.CheckErr(err)
+
+		unpacked, err := unpackCalldata(b, "sendCrossChainMessage")
+		cobra.CheckErr(err)
+		unpackedInput := *abi.ConvertType(
+			unpacked[0], new(teleportermessenger.TeleporterMessageInput),
+		).(*teleportermessenger.TeleporterMessageInput)
+		cobra.CheckErr(verifyRoundTrip(
+			newTeleporterMessageInputOutput(input), newTeleporterMessageInputOutput(unpackedInput)))
+
+		cobra.CheckErr(printEncoded(cmd, b))
+	},
+}
+
+var encodeRetryCmd = &cobra.Command{
+	Use:   "retry --source-blockchain-id BLOCKCHAIN_ID [--file FILE]",
+	Short: "Encodes the calldata of a retryMessageExecution call",
+	Long: `Encodes a TeleporterMessage struct and the ID of the blockchain it was sent from
+into the calldata of a call to the retryMessageExecution function of the
+TeleporterMessenger contract.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceBlockchainID, err := parseID(sourceBlockchainIDArg)
+		cobra.CheckErr(err)
+		message, err := encodeMessageFlags.teleporterMessage()
+		cobra.CheckErr(err)
+
+		b, err := teleportermessenger.PackRetryMessageExecution(sourceBlockchainID, message)
+		cobra.CheckErr(err)
+
+		unpacked, err := unpackCalldata(b, "retryMessageExecution")
+		cobra.CheckErr(err)
+		unpackedMessage := *abi.ConvertType(
+			unpacked[1], new(teleportermessenger.TeleporterMessage),
+		).(*teleportermessenger.TeleporterMessage)
+		cobra.CheckErr(verifyRoundTrip([32]byte(sourceBlockchainID), unpacked[0]))
+		cobra.CheckErr(verifyRoundTrip(
+			newTeleporterMessageOutput(message), newTeleporterMessageOutput(unpackedMessage)))
+
+		cobra.CheckErr(printEncoded(cmd, b))
+	},
+}
+
+var encodeReceiveCmd = &cobra.Command{
+	Use:   "receive --relayer-reward-address ADDRESS [--message-index INDEX]",
+	Short: "Encodes the calldata of a receiveCrossChainMessage call",
+	Long: `Encodes the index of the Warp message in the transaction's predicates and the
+relayer reward address into the calldata of a call to the receiveCrossChainMessage
+function of the TeleporterMessenger contract.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		rewardAddress, err := parseAddress(relayerRewardAddress)
+		cobra.CheckErr(err)
+
+		b, err := teleportermessenger.PackReceiveCrossChainMessage(messageIndex, rewardAddress)
+		cobra.CheckErr(err)
+
+		unpacked, err := unpackCalldata(b, "receiveCrossChainMessage")
+		cobra.CheckErr(err)
+		cobra.CheckErr(verifyRoundTrip(messageIndex, unpacked[0]))
+		cobra.CheckErr(verifyRoundTrip(rewardAddress, unpacked[1]))
+
+		cobra.CheckErr(printEncoded(cmd, b))
+	},
+}
+
+// teleporterMessage builds the TeleporterMessage described by the flags or JSON file
+func (f *messageFlags) teleporterMessage() (teleportermessenger.TeleporterMessage, error) {
+	messageOut := teleporterMessageOutput{
+		MessageNonce:            f.messageNonce,
+		OriginSenderAddress:     f.originSenderAddress,
+		DestinationBlockchainID: f.destinationBlockchainID,
+		DestinationAddress:      f.destinationAddress,
+		RequiredGasLimit:        f.requiredGasLimit,
+		AllowedRelayerAddresses: f.allowedRelayerAddresses,
+		Message:                 f.payload,
+	}
+	if f.payloadFile != "" {
+		payload, err := os.ReadFile(f.payloadFile)
+		if err != nil {
+			return teleportermessenger.TeleporterMessage{}, err
+		}
+		messageOut.Message = hexutil.Encode(payload)
+	}
+	for _, receipt := range f.receipts {
+		nonce, address, ok := strings.Cut(receipt, ":")
+		if !ok {
+			return teleportermessenger.TeleporterMessage{},
+				fmt.Errorf("invalid receipt %s, must be formatted as NONCE:RELAYER_REWARD_ADDRESS", receipt)
+		}
+		messageOut.Receipts = append(messageOut.Receipts, receiptOutput{
+			ReceivedMessageNonce: nonce,
+			RelayerRewardAddress: address,
+		})
+	}
+	if f.file != "" {
+		if err := readJSONFile(f.file, &messageOut); err != nil {
+			return teleportermessenger.TeleporterMessage{}, err
+		}
+	}
+	return messageOut.toTeleporterMessage()
+}
+
+// teleporterMessageInput builds the TeleporterMessageInput described by the flags or JSON file
+func (f *messageFlags) teleporterMessageInput() (teleportermessenger.TeleporterMessageInput, error) {
+	inputOut := teleporterMessageInputOutput{
+		DestinationBlockchainID: f.destinationBlockchainID,
+		DestinationAddress:      f.destinationAddress,
+		FeeInfo: &feeInfoOutput{
+			FeeTokenAddress: f.feeTokenAddress,
+			Amount:          f.feeAmount,
+		},
+		RequiredGasLimit:        f.requiredGasLimit,
+		AllowedRelayerAddresses: f.allowedRelayerAddresses,
+		Message:                 f.payload,
+	}
+	if f.payloadFile != "" {
+		payload, err := os.ReadFile(f.payloadFile)
+		if err != nil {
+			return teleportermessenger.TeleporterMessageInput{}, err
+		}
+		inputOut.Message = hexutil.Encode(payload)
+	}
+	if f.file != "" {
+		if err := readJSONFile(f.file, &inputOut); err != nil {
+			return teleportermessenger.TeleporterMessageInput{}, err
+		}
+	}
+	return inputOut.toTeleporterMessageInput()
+}
+
+func readJSONFile(file string, v interface{}) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return nil
+}
+
+// unpackCalldata checks that the calldata calls the given TeleporterMessenger method and unpacks its arguments
+func unpackCalldata(data []byte, method string) ([]interface{}, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata too short")
+	}
+	m, err := teleporterABI.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+	if m.Name != method {
+		return nil, fmt.Errorf("calldata calls %s, expected %s", m.Name, method)
+	}
+	return m.Inputs.Unpack(data[4:])
+}
+
+// verifyRoundTrip checks that a value decoded from the encoded bytes matches the value that was encoded
+func verifyRoundTrip(expected interface{}, decoded interface{}) error {
+	if !reflect.DeepEqual(expected, decoded) {
+		return fmt.Errorf("round trip verification failed: encoded %v, decoded %v", expected, decoded)
+	}
+	return nil
+}
+
+func printEncoded(cmd *cobra.Command, b []byte) error {
+	if !isTableOutput() {
+		return printOutput(cmd, encodedOutput{Data: hexutil.Encode(b)})
+	}
+	cmd.Println("Encoded", len(b), "bytes")
+	_, err := fmt.Fprintln(cmd.OutOrStdout(), hexutil.Encode(b))
+	return err
+}
+
+func addMessageFlags(cmd *cobra.Command, f *messageFlags) {
+	cmd.Flags().StringVar(&f.file, "file", "", "JSON file describing the TeleporterMessage, overriding the other flags")
+	cmd.Flags().StringVar(&f.messageNonce, "message-nonce", "", "Nonce of the message")
+	cmd.Flags().StringVar(&f.originSenderAddress, "origin-sender-address", "",
+		"Address of the sender of the message on the source chain")
+	cmd.Flags().StringVar(&f.destinationBlockchainID, "destination-blockchain-id", "",
+		"Blockchain ID of the destination chain, CB58 or hex encoded")
+	cmd.Flags().StringVar(&f.destinationAddress, "destination-address", "",
+		"Address of the recipient contract on the destination chain")
+	cmd.Flags().StringVar(&f.requiredGasLimit, "required-gas-limit", "",
+		"Gas limit required to execute the message on the destination chain")
+	cmd.Flags().StringSliceVar(&f.allowedRelayerAddresses, "allowed-relayers", []string{},
+		"Addresses of the relayers allowed to deliver the message")
+	cmd.Flags().StringSliceVar(&f.receipts, "receipts", []string{},
+		"Receipts included in the message, formatted as NONCE:RELAYER_REWARD_ADDRESS")
+	cmd.Flags().StringVar(&f.payload, "payload", "", "Hex encoded message payload")
+	cmd.Flags().StringVar(&f.payloadFile, "payload-file", "", "File holding the raw message payload bytes")
+	cmd.MarkFlagsMutuallyExclusive("payload", "payload-file")
+}
+
+func addMessageInputFlags(cmd *cobra.Command, f *messageFlags) {
+	cmd.Flags().StringVar(&f.file, "file", "", "JSON file describing the TeleporterMessageInput, overriding the other flags")
+	cmd.Flags().StringVar(&f.destinationBlockchainID, "destination-blockchain-id", "",
+		"Blockchain ID of the destination chain, CB58 or hex encoded")
+	cmd.Flags().StringVar(&f.destinationAddress, "destination-address", "",
+		"Address of the recipient contract on the destination chain")
+	cmd.Flags().StringVar(&f.feeTokenAddress, "fee-token", "", "Address of the ERC20 fee token")
+	cmd.Flags().StringVar(&f.feeAmount, "fee-amount", "", "Amount of the fee token to pay the relayer")
+	cmd.Flags().StringVar(&f.requiredGasLimit, "required-gas-limit", "",
+		"Gas limit required to execute the message on the destination chain")
+	cmd.Flags().StringSliceVar(&f.allowedRelayerAddresses, "allowed-relayers", []string{},
+		"Addresses of the relayers allowed to deliver the message")
+	cmd.Flags().StringVar(&f.payload, "payload", "", "Hex encoded message payload")
+	cmd.Flags().StringVar(&f.payloadFile, "payload-file", "", "File holding the raw message payload bytes")
+	cmd.MarkFlagsMutuallyExclusive("payload", "payload-file")
+}
+
+func init() {
+	rootCmd.AddCommand(encodeCmd)
+	encodeCmd.AddCommand(encodeMessageCmd, encodeSendCmd, encodeRetryCmd, encodeReceiveCmd)
+
+	addMessageFlags(encodeMessageCmd, &encodeMessageFlags)
+	addMessageInputFlags(encodeSendCmd, &encodeInputFlags)
+	addMessageFlags(encodeRetryCmd, &encodeMessageFlags)
+	encodeRetryCmd.Flags().StringVar(&sourceBlockchainIDArg, "source-blockchain-id", "",
+		"Blockchain ID of the chain the message was sent from, CB58 or hex encoded")
+	err := encodeRetryCmd.MarkFlagRequired("source-blockchain-id")
+	cobra.CheckErr(err)
+
+	encodeReceiveCmd.Flags().Uint32Var(&messageIndex, "message-index", 0,
+		"Index of the Warp message in the transaction's predicates")
+	encodeReceiveCmd.Flags().StringVar(&relayerRewardAddress, "relayer-reward-address", "",
+		"Address to credit the relayer rewards to")
+	err = encodeReceiveCmd.MarkFlagRequired("relayer-reward-address")
+	cobra.CheckErr(err)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "help",
+			args: []string{"encode", "--help"},
+			err:  nil,
+			out:  "Encodes a Teleporter message or the calldata of a Teleporter contract call",
+		},
+		{
+			name: "message help",
+			args: []string{"encode", "message", "--help"},
+			err:  nil,
+			out:  "Encodes a TeleporterMessage struct into the ABI encoded bytes",
+		},
+		{
+			name: "retry no source blockchain ID",
+			args: []string{"encode", "retry"},
+			err:  fmt.Errorf("required flag(s) \"source-blockchain-id\" not set"),
+		},
+		{
+			name: "receive no relayer reward address",
+			args: []string{"encode", "receive"},
+			err:  fmt.Errorf("required flag(s) \"relayer-reward-address\" not set"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func lastLine(out string) string {
+	lines := strings.Split(out, "\n")
+	return lines[len(lines)-1]
+}
+
+func TestEncodeMessageCmd(t *testing.T) {
+	out, err := executeTestCmd(t, rootCmd, "encode", "message",
+		"--message-nonce", "7",
+		"--origin-sender-address", "0x0123456789abcdef0123456789abcdef01234567",
+		"--destination-blockchain-id", ids.ID{1, 2, 3, 4}.String(),
+		"--destination-address", "0x0123456789abcdef0123456789abcdef01234567",
+		"--required-gas-limit", "100000",
+		"--allowed-relayers", "0x0123456789abcdef0123456789abcdef01234567",
+		"--receipts", "3:0x0123456789abcdef0123456789abcdef01234567",
+		"--payload", "0x01020304",
+	)
+	require.NoError(t, err)
+
+	b, err := hexutil.Decode(lastLine(out))
+	require.NoError(t, err)
+	message, err := teleportermessenger.UnpackTeleporterMessage(b)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), message.MessageNonce.Int64())
+	require.Equal(t, ids.ID{1, 2, 3, 4}, ids.ID(message.DestinationBlockchainID))
+	require.Equal(t, int64(100000), message.RequiredGasLimit.Int64())
+	require.Len(t, message.Receipts, 1)
+	require.Equal(t, int64(3), message.Receipts[0].ReceivedMessageNonce.Int64())
+	require.Equal(t, []byte{1, 2, 3, 4}, message.Message)
+}
+
+func TestEncodeSendCmdFile(t *testing.T) {
+	input := teleportermessenger.TeleporterMessageInput{
+		DestinationBlockchainID: ids.ID{5, 6, 7, 8},
+		DestinationAddress:      common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		FeeInfo: teleportermessenger.TeleporterFeeInfo{
+			FeeTokenAddress: common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+			Amount:          createTestTeleporterMessage().RequiredGasLimit,
+		},
+		RequiredGasLimit:        createTestTeleporterMessage().RequiredGasLimit,
+		AllowedRelayerAddresses: []common.Address{},
+		Message:                 []byte{1, 2, 3, 4},
+	}
+	b, err := json.Marshal(newTeleporterMessageInputOutput(input))
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "input.json")
+	require.NoError(t, os.WriteFile(file, b, 0o600))
+
+	out, err := executeTestCmd(t, rootCmd, "encode", "send", "--file", file)
+	require.NoError(t, err)
+
+	expected, err := teleportermessenger.PackSendCrossChainMessage(input)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Encode(expected), lastLine(out))
+}
+
+func TestEncodeReceiveCmd(t *testing.T) {
+	relayer := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+	out, err := executeTestCmd(t, rootCmd, "encode", "receive",
+		"--message-index", "1", "--relayer-reward-address", relayer.Hex())
+	require.NoError(t, err)
+
+	expected, err := teleportermessenger.PackReceiveCrossChainMessage(1, relayer)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Encode(expected), lastLine(out))
+}
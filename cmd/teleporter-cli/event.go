@@ -4,6 +4,7 @@
 package main
 
 import (
+	"github.com/ava-labs/subnet-evm/core/types"
 	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
@@ -31,13 +32,16 @@ func eventRun(cmd *cobra.Command, args []string) {
 		topics = append(topics, common.HexToHash(topic))
 	}
 
-	event, err := teleporterABI.EventByID(topics[0])
+	out, err := teleportermessenger.DecodeLog(types.Log{Topics: topics, Data: data})
 	cobra.CheckErr(err)
-
-	out, err := teleportermessenger.FilterTeleporterEvents(topics, data, event.Name)
-	cobra.CheckErr(err)
-	logger.Info("Parsed Teleporter event", zap.String("name", event.Name), zap.Any("event", out))
-	cmd.Println("Event command ran successfully for", event.Name)
+	if !isTableOutput() {
+		eventOut, err := newEventOutput(out, nil)
+		cobra.CheckErr(err)
+		cobra.CheckErr(printOutput(cmd, eventOut))
+		return
+	}
+	logger.Info("Parsed Teleporter event", zap.String("name", out.Kind().String()), zap.Any("event", out))
+	cmd.Println("Event command ran successfully for", out.Kind())
 }
 
 func init() {
@@ -4,18 +4,32 @@
 package main
 
 import (
+	"context"
 	"encoding/hex"
+	"strings"
 
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
 	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
+var (
+	payloadDecoderName string
+	payloadRPCEndpoint string
+)
+
 var messageCmd = &cobra.Command{
 	Use:   "message MESSAGE_BYTES",
 	Short: "Decodes hex encoded Teleporter message bytes into a TeleporterMessage struct",
 	Long: `Given the hex encoded bytes of a Teleporter message, this command will decode
-the bytes into a TeleporterMessage struct and print the struct fields.`,
+the bytes into a TeleporterMessage struct and print the struct fields.
+
+The message payload is decoded for the cross-chain applications in this repository
+selected by --payload-decoder. By default the application is detected by probing
+the destination contract's ABI on the chain at --destination-rpc, or, without
+--destination-rpc, from the payloads that only one application could have encoded.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		encodedMsg := args[0]
@@ -24,11 +38,38 @@ the bytes into a TeleporterMessage struct and print the struct fields.`,
 
 		msg, err := teleportermessenger.UnpackTeleporterMessage(b)
 		cobra.CheckErr(err)
+
+		var caller interfaces.ContractCaller
+		if payloadRPCEndpoint != "" {
+			c, err := ethclient.Dial(payloadRPCEndpoint)
+			cobra.CheckErr(err)
+			defer c.Close()
+			caller = c
+		}
+		payload, err := decodePayload(context.Background(), payloadDecoderName, caller, msg.DestinationAddress, msg.Message)
+		cobra.CheckErr(err)
+
+		if !isTableOutput() {
+			out := newTeleporterMessageOutput(*msg)
+			out.Payload = payload
+			cobra.CheckErr(printOutput(cmd, out))
+			return
+		}
 		logger.Info("Teleporter Message unpacked", zap.Any("message", msg))
+		if payload != nil {
+			logger.Info("Message payload decoded",
+				zap.String("decoder", payload.Decoder),
+				zap.String("action", payload.Action),
+				zap.Any("fields", payload.Fields))
+		}
 		cmd.Println("Message command ran successfully")
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(messageCmd)
+	messageCmd.Flags().StringVar(&payloadDecoderName, "payload-decoder", autoPayloadDecoder,
+		"Decoder of the message payload, one of "+strings.Join(payloadDecoderNames(), ", "))
+	messageCmd.Flags().StringVar(&payloadRPCEndpoint, "destination-rpc", "",
+		"RPC endpoint of the destination chain, used to detect the payload decoder from the destination contract")
 }
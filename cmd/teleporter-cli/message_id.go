@@ -0,0 +1,258 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	teleporterUtils "github.com/ava-labs/teleporter/utils/teleporter-utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// maxMessageIDRange is the maximum number of nonces that message IDs are computed for with --range
+const maxMessageIDRange = 10_000
+
+var (
+	messageIDTeleporterAddress       string
+	messageIDSourceBlockchainID      string
+	messageIDDestinationBlockchainID string
+	messageIDRange                   string
+	messageIDVerify                  bool
+)
+
+type messageIDOutput struct {
+	Nonce        string `json:"nonce" yaml:"nonce"`
+	MessageID    string `json:"messageID" yaml:"messageID"`
+	MessageIDHex string `json:"messageIDHex" yaml:"messageIDHex"`
+	// NonceUsed is set by --verify if the TeleporterMessenger has sent a message with the nonce, to any
+	// destination, since nonces are shared by all destinations.
+	NonceUsed *bool `json:"nonceUsed,omitempty" yaml:"nonceUsed,omitempty"`
+}
+
+type messageIDsOutput struct {
+	TeleporterAddress       string            `json:"teleporterAddress" yaml:"teleporterAddress"`
+	SourceBlockchainID      string            `json:"sourceBlockchainID" yaml:"sourceBlockchainID"`
+	DestinationBlockchainID string            `json:"destinationBlockchainID" yaml:"destinationBlockchainID"`
+	MessageIDs              []messageIDOutput `json:"messageIDs" yaml:"messageIDs"`
+	Verified                bool              `json:"verified" yaml:"verified"`
+	NextMessageNonce        string            `json:"nextMessageNonce,omitempty" yaml:"nextMessageNonce,omitempty"`
+	NextMessageID           string            `json:"nextMessageID,omitempty" yaml:"nextMessageID,omitempty"`
+}
+
+var messageIDCmd = &cobra.Command{
+	Use: "message-id --teleporter-address CONTRACT_ADDRESS --source-blockchain-id ID " +
+		"--destination-blockchain-id ID (NONCE | --range START:END)",
+	Short: "Computes Teleporter message IDs from message nonces",
+	Long: `Computes the ID of the message sent by the TeleporterMessenger contract from the
+source blockchain to the destination blockchain with the given nonce, or the IDs of
+the messages with each nonce in the inclusive --range START:END, and prints them
+CB58 and hex encoded. Blockchain IDs may be CB58 or hex encoded.
+
+With --verify, the IDs are cross-checked against the contract's calculateMessageID
+on the source chain at --rpc, and the ID of the next message to the destination
+is read from getNextMessageID.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		address, err := parseAddress(messageIDTeleporterAddress)
+		cobra.CheckErr(err)
+		sourceBlockchainID, err := parseID(messageIDSourceBlockchainID)
+		cobra.CheckErr(err)
+		destinationBlockchainID, err := parseID(messageIDDestinationBlockchainID)
+		cobra.CheckErr(err)
+		start, end, err := parseNonceRange(args, messageIDRange)
+		cobra.CheckErr(err)
+
+		out, err := computeMessageIDs(address, sourceBlockchainID, destinationBlockchainID, start, end)
+		cobra.CheckErr(err)
+		if messageIDVerify {
+			if rpcEndpoint == "" {
+				cobra.CheckErr(fmt.Errorf("--rpc must be provided with --verify"))
+			}
+			c, err := ethclient.Dial(rpcEndpoint)
+			cobra.CheckErr(err)
+			defer c.Close()
+			cobra.CheckErr(verifyMessageIDs(context.Background(), c, address, out))
+		}
+
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, out))
+			return
+		}
+		for _, id := range out.MessageIDs {
+			line := fmt.Sprintf("%s: %s %s", id.Nonce, id.MessageID, id.MessageIDHex)
+			if id.NonceUsed != nil && !*id.NonceUsed {
+				line += " (nonce not used yet)"
+			}
+			cmd.Println(line)
+		}
+		if out.NextMessageID != "" {
+			logger.Info("Next message",
+				zap.String("nonce", out.NextMessageNonce),
+				zap.String("messageID", out.NextMessageID))
+		}
+		cmd.Println("Message ID command ran successfully")
+	},
+}
+
+// parseNonceRange returns the nonce given as an argument as a range of one nonce, or the range START:END
+func parseNonceRange(args []string, nonceRange string) (*big.Int, *big.Int, error) {
+	if (len(args) == 0) == (nonceRange == "") {
+		return nil, nil, fmt.Errorf("either NONCE or --range must be provided")
+	}
+	if len(args) == 1 {
+		nonce, err := parseBigInt(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return nonce, nonce, nil
+	}
+
+	startArg, endArg, ok := strings.Cut(nonceRange, ":")
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid range %s: must be START:END", nonceRange)
+	}
+	start, err := parseBigInt(startArg)
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := parseBigInt(endArg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if start.Cmp(end) > 0 {
+		return nil, nil, fmt.Errorf("range start %s is after range end %s", start, end)
+	}
+	if new(big.Int).Sub(end, start).Cmp(big.NewInt(maxMessageIDRange)) >= 0 {
+		return nil, nil, fmt.Errorf("range must contain at most %d nonces", maxMessageIDRange)
+	}
+	return start, end, nil
+}
+
+// computeMessageIDs computes the IDs of the messages with each nonce in the inclusive range
+func computeMessageIDs(
+	teleporterAddress common.Address,
+	sourceBlockchainID ids.ID,
+	destinationBlockchainID ids.ID,
+	start *big.Int,
+	end *big.Int,
+) (*messageIDsOutput, error) {
+	if start.Sign() < 0 {
+		return nil, fmt.Errorf("nonce must not be negative")
+	}
+	out := &messageIDsOutput{
+		TeleporterAddress:       teleporterAddress.Hex(),
+		SourceBlockchainID:      sourceBlockchainID.String(),
+		DestinationBlockchainID: destinationBlockchainID.String(),
+		MessageIDs:              []messageIDOutput{},
+	}
+	for nonce := new(big.Int).Set(start); nonce.Cmp(end) <= 0; nonce = new(big.Int).Add(nonce, common.Big1) {
+		messageID, err := teleporterUtils.CalculateMessageID(
+			teleporterAddress, sourceBlockchainID, destinationBlockchainID, nonce)
+		if err != nil {
+			return nil, err
+		}
+		out.MessageIDs = append(out.MessageIDs, messageIDOutput{
+			Nonce:        nonce.String(),
+			MessageID:    messageID.String(),
+			MessageIDHex: hexutil.Encode(messageID[:]),
+		})
+	}
+	return out, nil
+}
+
+// verifyMessageIDs checks the computed message IDs against the TeleporterMessenger contract's calculateMessageID,
+// marks the nonces the contract has used, and sets the ID of the next message to the destination.
+func verifyMessageIDs(
+	ctx context.Context,
+	caller bind.ContractCaller,
+	teleporterAddress common.Address,
+	out *messageIDsOutput,
+) error {
+	teleporter, err := teleportermessenger.NewTeleporterMessengerCaller(teleporterAddress, caller)
+	if err != nil {
+		return err
+	}
+	sourceBlockchainID, err := ids.FromString(out.SourceBlockchainID)
+	if err != nil {
+		return err
+	}
+	destinationBlockchainID, err := ids.FromString(out.DestinationBlockchainID)
+	if err != nil {
+		return err
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	// The blockchain ID is only initialized once the contract has sent or received a message
+	blockchainID, err := teleporter.BlockchainID(opts)
+	if err != nil {
+		return err
+	}
+	if blockchainID != [32]byte{} && blockchainID != sourceBlockchainID {
+		return fmt.Errorf("the TeleporterMessenger's blockchain ID %s does not match the source blockchain ID %s, "+
+			"--rpc must point to the source chain", ids.ID(blockchainID), sourceBlockchainID)
+	}
+	latestNonce, err := teleporter.MessageNonce(opts)
+	if err != nil {
+		return err
+	}
+
+	for i := range out.MessageIDs {
+		id := &out.MessageIDs[i]
+		nonce, ok := new(big.Int).SetString(id.Nonce, 10)
+		if !ok {
+			return fmt.Errorf("invalid nonce %s", id.Nonce)
+		}
+		onChainID, err := teleporter.CalculateMessageID(opts, sourceBlockchainID, destinationBlockchainID, nonce)
+		if err != nil {
+			return err
+		}
+		if ids.ID(onChainID).String() != id.MessageID {
+			return fmt.Errorf("message ID of nonce %s is %s on-chain, but computed as %s",
+				id.Nonce, ids.ID(onChainID), id.MessageID)
+		}
+		nonceUsed := nonce.Sign() > 0 && nonce.Cmp(latestNonce) <= 0
+		id.NonceUsed = &nonceUsed
+	}
+
+	nextNonce := new(big.Int).Add(latestNonce, common.Big1)
+	out.NextMessageNonce = nextNonce.String()
+	nextMessageID, err := teleporter.GetNextMessageID(opts, destinationBlockchainID)
+	if err != nil {
+		// getNextMessageID reverts until the contract's blockchain ID is initialized
+		logger.Warn("Failed to get next message ID", zap.Error(err))
+	} else {
+		out.NextMessageID = ids.ID(nextMessageID).String()
+	}
+	out.Verified = true
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(messageIDCmd)
+	messageIDCmd.Flags().StringVarP(&messageIDTeleporterAddress, "teleporter-address", "t", "",
+		"Teleporter contract address")
+	messageIDCmd.Flags().StringVar(&messageIDSourceBlockchainID, "source-blockchain-id", "",
+		"Blockchain ID of the chain the messages are sent from")
+	messageIDCmd.Flags().StringVar(&messageIDDestinationBlockchainID, "destination-blockchain-id", "",
+		"Blockchain ID of the chain the messages are sent to")
+	messageIDCmd.Flags().StringVar(&messageIDRange, "range", "",
+		"Inclusive range of nonces START:END to compute message IDs for, instead of NONCE")
+	messageIDCmd.Flags().BoolVar(&messageIDVerify, "verify", false,
+		"Check the message IDs against the TeleporterMessenger contract on the source chain at --rpc")
+	messageIDCmd.Flags().StringVar(&rpcEndpoint, "rpc", "", "RPC endpoint of the source chain, used by --verify")
+	for _, flag := range []string{"teleporter-address", "source-blockchain-id", "destination-blockchain-id"} {
+		err := messageIDCmd.MarkFlagRequired(flag)
+		cobra.CheckErr(err)
+	}
+}
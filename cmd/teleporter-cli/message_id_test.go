@@ -0,0 +1,198 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMessageIDSourceBlockchainID      = "2D8RG4UpSXbPbvPCAWppNJyqTG2i2CAXSkTgmTBBvs7GKNZjsY"
+	testMessageIDDestinationBlockchainID = "yH8D7ThNJkxmtkuv2jgBa4P1Rn3Qpr4pPr7QYNfcdoS6k6HWp"
+)
+
+var testMessageIDTeleporterAddress = common.HexToAddress("0xfeabb3b3f4eeae6b5769507a5e6b808704e5c626")
+
+func TestMessageIDCmd(t *testing.T) {
+	t.Cleanup(func() { outputFormat = tableOutput })
+
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "too many args",
+			args: []string{"message-id", "1", "2"},
+			err:  fmt.Errorf("accepts at most 1 arg(s), received 2"),
+		},
+		{
+			name: "help",
+			args: []string{"message-id", "--help"},
+			out:  "Computes the ID of the message sent by the TeleporterMessenger contract",
+		},
+		{
+			name: "nonce",
+			args: []string{
+				"message-id", "--output", jsonOutput,
+				"--teleporter-address", testMessageIDTeleporterAddress.Hex(),
+				"--source-blockchain-id", testMessageIDSourceBlockchainID,
+				"--destination-blockchain-id", testMessageIDDestinationBlockchainID,
+				"1",
+			},
+			out: `"messageID": "ZyaPKmkZwJTJNKkUjvNLVAd5rGhcj7pUC61rtQh93Z6Ue2Fxu"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestParseNonceRange(t *testing.T) {
+	var tests = []struct {
+		name  string
+		args  []string
+		rng   string
+		start int64
+		end   int64
+		err   string
+	}{
+		{
+			name:  "nonce",
+			args:  []string{"7"},
+			start: 7,
+			end:   7,
+		},
+		{
+			name:  "range",
+			rng:   "3:5",
+			start: 3,
+			end:   5,
+		},
+		{
+			name: "nonce and range",
+			args: []string{"7"},
+			rng:  "3:5",
+			err:  "either NONCE or --range must be provided",
+		},
+		{
+			name: "neither",
+			err:  "either NONCE or --range must be provided",
+		},
+		{
+			name: "missing separator",
+			rng:  "3",
+			err:  "must be START:END",
+		},
+		{
+			name: "reversed",
+			rng:  "5:3",
+			err:  "range start 5 is after range end 3",
+		},
+		{
+			name: "too large",
+			rng:  fmt.Sprintf("1:%d", maxMessageIDRange+1),
+			err:  "range must contain at most",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseNonceRange(tt.args, tt.rng)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, big.NewInt(tt.start), start)
+			require.Equal(t, big.NewInt(tt.end), end)
+		})
+	}
+}
+
+func TestComputeMessageIDs(t *testing.T) {
+	sourceBlockchainID, err := ids.FromString(testMessageIDSourceBlockchainID)
+	require.NoError(t, err)
+	destinationBlockchainID, err := ids.FromString(testMessageIDDestinationBlockchainID)
+	require.NoError(t, err)
+
+	out, err := computeMessageIDs(testMessageIDTeleporterAddress, sourceBlockchainID, destinationBlockchainID,
+		big.NewInt(1), big.NewInt(3))
+	require.NoError(t, err)
+	require.Len(t, out.MessageIDs, 3)
+	for i, id := range out.MessageIDs {
+		require.Equal(t, fmt.Sprint(i+1), id.Nonce)
+		messageID, err := ids.FromString(id.MessageID)
+		require.NoError(t, err)
+		require.Equal(t, "0x"+messageID.Hex(), id.MessageIDHex)
+	}
+	require.Equal(t, "ZyaPKmkZwJTJNKkUjvNLVAd5rGhcj7pUC61rtQh93Z6Ue2Fxu", out.MessageIDs[0].MessageID)
+	require.False(t, out.Verified)
+
+	_, err = computeMessageIDs(testMessageIDTeleporterAddress, sourceBlockchainID, destinationBlockchainID,
+		big.NewInt(-1), big.NewInt(1))
+	require.ErrorContains(t, err, "nonce must not be negative")
+}
+
+func TestVerifyMessageIDs(t *testing.T) {
+	ctx := context.Background()
+	backend, key := newTestBackend(t)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, simulatedChainID)
+	require.NoError(t, err)
+	opts.Context = ctx
+
+	teleporterAddress, tx, _, err := teleportermessenger.DeployTeleporterMessenger(opts, backend)
+	require.NoError(t, err)
+	_, err = waitForSuccess(ctx, backend, tx)
+	require.NoError(t, err)
+
+	sourceBlockchainID, err := ids.FromString(testMessageIDSourceBlockchainID)
+	require.NoError(t, err)
+	destinationBlockchainID, err := ids.FromString(testMessageIDDestinationBlockchainID)
+	require.NoError(t, err)
+
+	t.Run("matching IDs", func(t *testing.T) {
+		out, err := computeMessageIDs(teleporterAddress, sourceBlockchainID, destinationBlockchainID,
+			big.NewInt(0), big.NewInt(2))
+		require.NoError(t, err)
+		require.NoError(t, verifyMessageIDs(ctx, backend, teleporterAddress, out))
+		require.True(t, out.Verified)
+		for _, id := range out.MessageIDs {
+			require.NotNil(t, id.NonceUsed)
+			require.False(t, *id.NonceUsed)
+		}
+		require.Equal(t, "1", out.NextMessageNonce)
+		// getNextMessageID reverts since the blockchain ID is not initialized on the simulated backend
+		require.Empty(t, out.NextMessageID)
+	})
+
+	t.Run("mismatched IDs", func(t *testing.T) {
+		// IDs computed for a different contract address do not match the deployed contract's
+		out, err := computeMessageIDs(testMessageIDTeleporterAddress, sourceBlockchainID, destinationBlockchainID,
+			big.NewInt(1), big.NewInt(1))
+		require.NoError(t, err)
+		err = verifyMessageIDs(ctx, backend, teleporterAddress, out)
+		require.ErrorContains(t, err, "message ID of nonce 1 is")
+		require.False(t, out.Verified)
+	})
+}
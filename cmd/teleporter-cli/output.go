@@ -0,0 +1,409 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	tableOutput = "table"
+	jsonOutput  = "json"
+	yamlOutput  = "yaml"
+)
+
+var outputFormat string
+
+// The output types below define the stable schema used by the json and yaml output formats.
+// Bytes and addresses are hex encoded, big integers are decimal strings, and blockchain and
+// message IDs are CB58 encoded.
+
+type feeInfoOutput struct {
+	FeeTokenAddress string `json:"feeTokenAddress" yaml:"feeTokenAddress"`
+	Amount          string `json:"amount" yaml:"amount"`
+}
+
+type receiptOutput struct {
+	ReceivedMessageNonce string `json:"receivedMessageNonce" yaml:"receivedMessageNonce"`
+	RelayerRewardAddress string `json:"relayerRewardAddress" yaml:"relayerRewardAddress"`
+}
+
+type teleporterMessageOutput struct {
+	MessageNonce            string          `json:"messageNonce" yaml:"messageNonce"`
+	OriginSenderAddress     string          `json:"originSenderAddress" yaml:"originSenderAddress"`
+	DestinationBlockchainID string          `json:"destinationBlockchainID" yaml:"destinationBlockchainID"`
+	DestinationAddress      string          `json:"destinationAddress" yaml:"destinationAddress"`
+	RequiredGasLimit        string          `json:"requiredGasLimit" yaml:"requiredGasLimit"`
+	AllowedRelayerAddresses []string        `json:"allowedRelayerAddresses" yaml:"allowedRelayerAddresses"`
+	Receipts                []receiptOutput `json:"receipts" yaml:"receipts"`
+	Message                 string          `json:"message" yaml:"message"`
+	Payload                 *payloadOutput  `json:"payload,omitempty" yaml:"payload,omitempty"`
+}
+
+type teleporterMessageInputOutput struct {
+	DestinationBlockchainID string         `json:"destinationBlockchainID" yaml:"destinationBlockchainID"`
+	DestinationAddress      string         `json:"destinationAddress" yaml:"destinationAddress"`
+	FeeInfo                 *feeInfoOutput `json:"feeInfo" yaml:"feeInfo"`
+	RequiredGasLimit        string         `json:"requiredGasLimit" yaml:"requiredGasLimit"`
+	AllowedRelayerAddresses []string       `json:"allowedRelayerAddresses" yaml:"allowedRelayerAddresses"`
+	Message                 string         `json:"message" yaml:"message"`
+}
+
+// eventOutput is the output of any Teleporter event. Only the fields present
+// in the event, and the log metadata if known, are set.
+type eventOutput struct {
+	Name                    string                   `json:"name" yaml:"name"`
+	Address                 string                   `json:"address,omitempty" yaml:"address,omitempty"`
+	TeleporterVersion       string                   `json:"teleporterVersion,omitempty" yaml:"teleporterVersion,omitempty"`
+	BlockNumber             *uint64                  `json:"blockNumber,omitempty" yaml:"blockNumber,omitempty"`
+	TransactionHash         string                   `json:"transactionHash,omitempty" yaml:"transactionHash,omitempty"`
+	LogIndex                *uint                    `json:"logIndex,omitempty" yaml:"logIndex,omitempty"`
+	MessageID               string                   `json:"messageID,omitempty" yaml:"messageID,omitempty"`
+	BlockchainID            string                   `json:"blockchainID,omitempty" yaml:"blockchainID,omitempty"`
+	SourceBlockchainID      string                   `json:"sourceBlockchainID,omitempty" yaml:"sourceBlockchainID,omitempty"`
+	DestinationBlockchainID string                   `json:"destinationBlockchainID,omitempty" yaml:"destinationBlockchainID,omitempty"`
+	Deliverer               string                   `json:"deliverer,omitempty" yaml:"deliverer,omitempty"`
+	RewardRedeemer          string                   `json:"rewardRedeemer,omitempty" yaml:"rewardRedeemer,omitempty"`
+	RelayerRewardAddress    string                   `json:"relayerRewardAddress,omitempty" yaml:"relayerRewardAddress,omitempty"`
+	Redeemer                string                   `json:"redeemer,omitempty" yaml:"redeemer,omitempty"`
+	Asset                   string                   `json:"asset,omitempty" yaml:"asset,omitempty"`
+	Amount                  string                   `json:"amount,omitempty" yaml:"amount,omitempty"`
+	Message                 *teleporterMessageOutput `json:"message,omitempty" yaml:"message,omitempty"`
+	FeeInfo                 *feeInfoOutput           `json:"feeInfo,omitempty" yaml:"feeInfo,omitempty"`
+	UpdatedFeeInfo          *feeInfoOutput           `json:"updatedFeeInfo,omitempty" yaml:"updatedFeeInfo,omitempty"`
+}
+
+func bigIntOutput(b *big.Int) string {
+	if b == nil {
+		return "0"
+	}
+	return b.String()
+}
+
+func newFeeInfoOutput(feeInfo teleportermessenger.TeleporterFeeInfo) *feeInfoOutput {
+	return &feeInfoOutput{
+		FeeTokenAddress: feeInfo.FeeTokenAddress.Hex(),
+		Amount:          bigIntOutput(feeInfo.Amount),
+	}
+}
+
+func newTeleporterMessageOutput(message teleportermessenger.TeleporterMessage) *teleporterMessageOutput {
+	out := &teleporterMessageOutput{
+		MessageNonce:            bigIntOutput(message.MessageNonce),
+		OriginSenderAddress:     message.OriginSenderAddress.Hex(),
+		DestinationBlockchainID: ids.ID(message.DestinationBlockchainID).String(),
+		DestinationAddress:      message.DestinationAddress.Hex(),
+		RequiredGasLimit:        bigIntOutput(message.RequiredGasLimit),
+		AllowedRelayerAddresses: []string{},
+		Receipts:                []receiptOutput{},
+		Message:                 hexutil.Encode(message.Message),
+	}
+	for _, relayer := range message.AllowedRelayerAddresses {
+		out.AllowedRelayerAddresses = append(out.AllowedRelayerAddresses, relayer.Hex())
+	}
+	for _, receipt := range message.Receipts {
+		out.Receipts = append(out.Receipts, receiptOutput{
+			ReceivedMessageNonce: bigIntOutput(receipt.ReceivedMessageNonce),
+			RelayerRewardAddress: receipt.RelayerRewardAddress.Hex(),
+		})
+	}
+	return out
+}
+
+func newTeleporterMessageInputOutput(input teleportermessenger.TeleporterMessageInput) *teleporterMessageInputOutput {
+	out := &teleporterMessageInputOutput{
+		DestinationBlockchainID: ids.ID(input.DestinationBlockchainID).String(),
+		DestinationAddress:      input.DestinationAddress.Hex(),
+		FeeInfo:                 newFeeInfoOutput(input.FeeInfo),
+		RequiredGasLimit:        bigIntOutput(input.RequiredGasLimit),
+		AllowedRelayerAddresses: []string{},
+		Message:                 hexutil.Encode(input.Message),
+	}
+	for _, relayer := range input.AllowedRelayerAddresses {
+		out.AllowedRelayerAddresses = append(out.AllowedRelayerAddresses, relayer.Hex())
+	}
+	return out
+}
+
+// toTeleporterFeeInfo parses the output representation of a fee info back into its binding type
+func (o *feeInfoOutput) toTeleporterFeeInfo() (teleportermessenger.TeleporterFeeInfo, error) {
+	if o == nil {
+		return teleportermessenger.TeleporterFeeInfo{Amount: big.NewInt(0)}, nil
+	}
+	feeTokenAddress, err := parseAddress(o.FeeTokenAddress)
+	if err != nil {
+		return teleportermessenger.TeleporterFeeInfo{}, err
+	}
+	amount, err := parseBigInt(o.Amount)
+	if err != nil {
+		return teleportermessenger.TeleporterFeeInfo{}, err
+	}
+	return teleportermessenger.TeleporterFeeInfo{
+		FeeTokenAddress: feeTokenAddress,
+		Amount:          amount,
+	}, nil
+}
+
+// toTeleporterMessage parses the output representation of a message back into its binding type
+func (o *teleporterMessageOutput) toTeleporterMessage() (teleportermessenger.TeleporterMessage, error) {
+	var message teleportermessenger.TeleporterMessage
+	nonce, err := parseBigInt(o.MessageNonce)
+	if err != nil {
+		return message, err
+	}
+	message.MessageNonce = nonce
+	if message.OriginSenderAddress, err = parseAddress(o.OriginSenderAddress); err != nil {
+		return message, err
+	}
+	destinationBlockchainID, err := parseID(o.DestinationBlockchainID)
+	if err != nil {
+		return message, err
+	}
+	message.DestinationBlockchainID = destinationBlockchainID
+	if message.DestinationAddress, err = parseAddress(o.DestinationAddress); err != nil {
+		return message, err
+	}
+	if message.RequiredGasLimit, err = parseBigInt(o.RequiredGasLimit); err != nil {
+		return message, err
+	}
+	if message.AllowedRelayerAddresses, err = parseAddresses(o.AllowedRelayerAddresses); err != nil {
+		return message, err
+	}
+	message.Receipts = []teleportermessenger.TeleporterMessageReceipt{}
+	for _, receipt := range o.Receipts {
+		receivedNonce, err := parseBigInt(receipt.ReceivedMessageNonce)
+		if err != nil {
+			return message, err
+		}
+		relayerRewardAddress, err := parseAddress(receipt.RelayerRewardAddress)
+		if err != nil {
+			return message, err
+		}
+		message.Receipts = append(message.Receipts, teleportermessenger.TeleporterMessageReceipt{
+			ReceivedMessageNonce: receivedNonce,
+			RelayerRewardAddress: relayerRewardAddress,
+		})
+	}
+	if message.Message, err = parseHexBytes(o.Message); err != nil {
+		return message, err
+	}
+	return message, nil
+}
+
+// toTeleporterMessageInput parses the output representation of a message input back into its binding type
+func (o *teleporterMessageInputOutput) toTeleporterMessageInput() (teleportermessenger.TeleporterMessageInput, error) {
+	var input teleportermessenger.TeleporterMessageInput
+	destinationBlockchainID, err := parseID(o.DestinationBlockchainID)
+	if err != nil {
+		return input, err
+	}
+	input.DestinationBlockchainID = destinationBlockchainID
+	if input.DestinationAddress, err = parseAddress(o.DestinationAddress); err != nil {
+		return input, err
+	}
+	if input.FeeInfo, err = o.FeeInfo.toTeleporterFeeInfo(); err != nil {
+		return input, err
+	}
+	if input.RequiredGasLimit, err = parseBigInt(o.RequiredGasLimit); err != nil {
+		return input, err
+	}
+	if input.AllowedRelayerAddresses, err = parseAddresses(o.AllowedRelayerAddresses); err != nil {
+		return input, err
+	}
+	if input.Message, err = parseHexBytes(o.Message); err != nil {
+		return input, err
+	}
+	return input, nil
+}
+
+// newEventOutput converts a parsed Teleporter event into its output representation.
+// If log is non-nil, its metadata is included in the output.
+func newEventOutput(event interface{}, log *types.Log) (*eventOutput, error) {
+	out := &eventOutput{}
+	switch e := event.(type) {
+	case *teleportermessenger.TeleporterMessengerSendCrossChainMessage:
+		out.Name = teleportermessenger.SendCrossChainMessage.String()
+		out.MessageID = ids.ID(e.MessageID).String()
+		out.DestinationBlockchainID = ids.ID(e.DestinationBlockchainID).String()
+		out.Message = newTeleporterMessageOutput(e.Message)
+		out.FeeInfo = newFeeInfoOutput(e.FeeInfo)
+	case *teleportermessenger.TeleporterMessengerReceiveCrossChainMessage:
+		out.Name = teleportermessenger.ReceiveCrossChainMessage.String()
+		out.MessageID = ids.ID(e.MessageID).String()
+		out.SourceBlockchainID = ids.ID(e.SourceBlockchainID).String()
+		out.Deliverer = e.Deliverer.Hex()
+		out.RewardRedeemer = e.RewardRedeemer.Hex()
+		out.Message = newTeleporterMessageOutput(e.Message)
+	case *teleportermessenger.TeleporterMessengerAddFeeAmount:
+		out.Name = teleportermessenger.AddFeeAmount.String()
+		out.MessageID = ids.ID(e.MessageID).String()
+		out.UpdatedFeeInfo = newFeeInfoOutput(e.UpdatedFeeInfo)
+	case *teleportermessenger.TeleporterMessengerMessageExecutionFailed:
+		out.Name = teleportermessenger.MessageExecutionFailed.String()
+		out.MessageID = ids.ID(e.MessageID).String()
+		out.SourceBlockchainID = ids.ID(e.SourceBlockchainID).String()
+		out.Message = newTeleporterMessageOutput(e.Message)
+	case *teleportermessenger.TeleporterMessengerMessageExecuted:
+		out.Name = teleportermessenger.MessageExecuted.String()
+		out.MessageID = ids.ID(e.MessageID).String()
+		out.SourceBlockchainID = ids.ID(e.SourceBlockchainID).String()
+	case *teleportermessenger.TeleporterMessengerRelayerRewardsRedeemed:
+		out.Name = teleportermessenger.RelayerRewardsRedeemed.String()
+		out.Redeemer = e.Redeemer.Hex()
+		out.Asset = e.Asset.Hex()
+		out.Amount = bigIntOutput(e.Amount)
+	case *teleportermessenger.TeleporterMessengerReceiptReceived:
+		out.Name = teleportermessenger.ReceiptReceived.String()
+		out.MessageID = ids.ID(e.MessageID).String()
+		out.DestinationBlockchainID = ids.ID(e.DestinationBlockchainID).String()
+		out.RelayerRewardAddress = e.RelayerRewardAddress.Hex()
+		out.FeeInfo = newFeeInfoOutput(e.FeeInfo)
+	case *teleportermessenger.TeleporterMessengerBlockchainIDInitialized:
+		out.Name = teleportermessenger.BlockchainIDInitialized.String()
+		out.BlockchainID = ids.ID(e.BlockchainID).String()
+	default:
+		return nil, fmt.Errorf("unsupported event type %T", event)
+	}
+
+	if log != nil {
+		blockNumber, logIndex := log.BlockNumber, log.Index
+		out.Address = log.Address.Hex()
+		out.BlockNumber = &blockNumber
+		out.TransactionHash = log.TxHash.Hex()
+		out.LogIndex = &logIndex
+	}
+	return out, nil
+}
+
+// logOutput is the output of a decoded Teleporter event or Warp message sent by the TeleporterMessenger contract
+type logOutput struct {
+	BlockNumber     uint64             `json:"blockNumber" yaml:"blockNumber"`
+	TransactionHash string             `json:"transactionHash" yaml:"transactionHash"`
+	Removed         bool               `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Event           *eventOutput       `json:"event,omitempty" yaml:"event,omitempty"`
+	WarpMessage     *warpMessageOutput `json:"warpMessage,omitempty" yaml:"warpMessage,omitempty"`
+}
+
+// name returns the name of the Teleporter event, or warpMessageEventName for Warp messages
+func (o *logOutput) name() string {
+	if o.Event != nil {
+		return o.Event.Name
+	}
+	return warpMessageEventName
+}
+
+// message returns the Teleporter message contained in the event or Warp message, if any
+func (o *logOutput) message() *teleporterMessageOutput {
+	if o.Event != nil {
+		return o.Event.Message
+	}
+	return o.WarpMessage.Message
+}
+
+// destinationBlockchainID returns the destination blockchain ID of the event or its message, if any
+func (o *logOutput) destinationBlockchainID() string {
+	if o.Event != nil && o.Event.DestinationBlockchainID != "" {
+		return o.Event.DestinationBlockchainID
+	}
+	if message := o.message(); message != nil {
+		return message.DestinationBlockchainID
+	}
+	return ""
+}
+
+// decodeLogOutput decodes a Teleporter event or a Warp message sent by the TeleporterMessenger contract.
+// Nil is returned for Warp messages sent by other contracts.
+func decodeLogOutput(log types.Log) (*logOutput, error) {
+	out := &logOutput{
+		BlockNumber:     log.BlockNumber,
+		TransactionHash: log.TxHash.Hex(),
+		Removed:         log.Removed,
+	}
+	if log.Address == warp.ContractAddress {
+		// Warp messages that do not contain a Teleporter message were not sent by Teleporter, and are skipped
+		unsignedMsg, addressedCall, teleporterMessage, err := parseTeleporterWarpLog(log)
+		if err != nil {
+			return nil, nil
+		}
+		if common.BytesToAddress(addressedCall.SourceAddress) != teleporterAddress {
+			return nil, nil
+		}
+		out.WarpMessage = &warpMessageOutput{
+			WarpMessageID:      unsignedMsg.ID().String(),
+			SourceBlockchainID: unsignedMsg.SourceChainID.String(),
+			SourceAddress:      common.BytesToAddress(addressedCall.SourceAddress).Hex(),
+			Message:            newTeleporterMessageOutput(*teleporterMessage),
+		}
+		return out, nil
+	}
+
+	event, err := teleportermessenger.DecodeLog(log)
+	if err != nil {
+		return nil, err
+	}
+	out.Event, err = newEventOutput(event, &log)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// isTableOutput returns true if the human readable output format is selected
+func isTableOutput() bool {
+	return outputFormat == tableOutput
+}
+
+// printOutput writes v to the command's output stream in the selected machine readable format.
+func printOutput(cmd *cobra.Command, v interface{}) error {
+	switch outputFormat {
+	case jsonOutput:
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case yamlOutput:
+		encoder := yaml.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("output format %s is not machine readable", outputFormat)
+	}
+}
+
+// printStreamOutput writes v to the command's output stream as one of a stream of documents.
+// JSON documents are printed on a single line each, and YAML documents are separated by "---".
+func printStreamOutput(cmd *cobra.Command, v interface{}) error {
+	switch outputFormat {
+	case jsonOutput:
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(v)
+	case yamlOutput:
+		fmt.Fprintln(cmd.OutOrStdout(), "---")
+		return printOutput(cmd, v)
+	default:
+		return fmt.Errorf("output format %s is not machine readable", outputFormat)
+	}
+}
+
+func validateOutputFormat(format string) error {
+	switch format {
+	case tableOutput, jsonOutput, yamlOutput:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %s, must be one of %s, %s, %s",
+			format, tableOutput, jsonOutput, yamlOutput)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func createTestTeleporterMessage() teleportermessenger.TeleporterMessage {
+	return teleportermessenger.TeleporterMessage{
+		MessageNonce:            big.NewInt(1),
+		OriginSenderAddress:     common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		DestinationBlockchainID: ids.ID{1, 2, 3, 4},
+		DestinationAddress:      common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		RequiredGasLimit:        new(big.Int).Lsh(big.NewInt(1), 70),
+		AllowedRelayerAddresses: []common.Address{
+			common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		},
+		Receipts: []teleportermessenger.TeleporterMessageReceipt{
+			{
+				ReceivedMessageNonce: big.NewInt(2),
+				RelayerRewardAddress: common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+			},
+		},
+		Message: []byte{1, 2, 3, 4},
+	}
+}
+
+func TestNewEventOutput(t *testing.T) {
+	message := createTestTeleporterMessage()
+	event := &teleportermessenger.TeleporterMessengerSendCrossChainMessage{
+		MessageID:               ids.ID{9, 10, 11, 12},
+		DestinationBlockchainID: ids.ID{1, 2, 3, 4},
+		Message:                 message,
+		FeeInfo: teleportermessenger.TeleporterFeeInfo{
+			FeeTokenAddress: common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+			Amount:          big.NewInt(5),
+		},
+	}
+
+	out, err := newEventOutput(event, nil)
+	require.NoError(t, err)
+	require.Equal(t, "SendCrossChainMessage", out.Name)
+	require.Equal(t, ids.ID{9, 10, 11, 12}.String(), out.MessageID)
+	require.Equal(t, ids.ID{1, 2, 3, 4}.String(), out.DestinationBlockchainID)
+	require.Equal(t, "1180591620717411303424", out.Message.RequiredGasLimit)
+	require.Equal(t, "0x01020304", out.Message.Message)
+	require.Equal(t, event.FeeInfo.FeeTokenAddress.Hex(), out.FeeInfo.FeeTokenAddress)
+	require.Equal(t, "5", out.FeeInfo.Amount)
+	require.Nil(t, out.BlockNumber)
+
+	_, err = newEventOutput(message, nil)
+	require.Error(t, err)
+}
+
+func TestMessageCmdOutput(t *testing.T) {
+	t.Cleanup(func() { outputFormat = tableOutput })
+
+	b, err := teleportermessenger.PackTeleporterMessage(createTestTeleporterMessage())
+	require.NoError(t, err)
+	encoded := hex.EncodeToString(b)
+
+	var tests = []struct {
+		name      string
+		format    string
+		unmarshal func([]byte, interface{}) error
+	}{
+		{
+			name:      "json",
+			format:    jsonOutput,
+			unmarshal: json.Unmarshal,
+		},
+		{
+			name:      "yaml",
+			format:    yamlOutput,
+			unmarshal: yaml.Unmarshal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, "message", encoded, "--output", tt.format)
+			require.NoError(t, err)
+
+			var messageOut teleporterMessageOutput
+			require.NoError(t, tt.unmarshal([]byte(out), &messageOut))
+			require.Equal(t, "1", messageOut.MessageNonce)
+			require.Equal(t, ids.ID{1, 2, 3, 4}.String(), messageOut.DestinationBlockchainID)
+			require.Equal(t, "0x01020304", messageOut.Message)
+			require.Len(t, messageOut.Receipts, 1)
+			require.Equal(t, "2", messageOut.Receipts[0].ReceivedMessageNonce)
+		})
+	}
+
+	_, err = executeTestCmd(t, rootCmd, "message", encoded, "--output", "xml")
+	require.ErrorContains(t, err, "invalid output format xml")
+}
@@ -0,0 +1,354 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	erc20bridge "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/ERC20Bridge/ERC20Bridge"
+	examplecrosschainmessenger "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/ExampleMessenger/ExampleCrossChainMessenger"
+	erc20tokensource "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/NativeTokenBridge/ERC20TokenSource"
+	nativetokendestination "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/NativeTokenBridge/NativeTokenDestination"
+	nativetokensource "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/NativeTokenBridge/NativeTokenSource"
+	blockhashreceiver "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/VerifiedBlockHash/BlockHashReceiver"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	autoPayloadDecoder = "auto"
+	noPayloadDecoder   = "none"
+)
+
+// Actions of the ERC20Bridge's BridgeAction enum, in the order defined in IERC20Bridge.sol
+var erc20BridgeActions = []string{"create", "mint", "transfer"}
+
+// sourceChainBurnAddress is the NativeTokenDestination's SOURCE_CHAIN_BURN_ADDRESS, used as the recipient
+// of the messages reporting the transaction fees burned on the destination chain.
+var sourceChainBurnAddress = common.HexToAddress("0x0100000000000000000000000000000000010203")
+
+// payloadOutput is an application payload of a Teleporter message decoded by a payloadDecoder
+type payloadOutput struct {
+	Decoder string            `json:"decoder" yaml:"decoder"`
+	Action  string            `json:"action" yaml:"action"`
+	Fields  map[string]string `json:"fields" yaml:"fields"`
+}
+
+// contractProbe is a call to a view function that identifies a contract by its ABI
+type contractProbe struct {
+	metaData *bind.MetaData
+	method   string
+	args     []interface{}
+}
+
+// payloadDecoder decodes the messages sent to the contracts of a cross-chain application
+type payloadDecoder struct {
+	name string
+	// probes identify the contracts of the application that receive its messages
+	probes []contractProbe
+	decode func(payload []byte) (*payloadOutput, error)
+}
+
+// payloadDecoders are the decoders of the cross-chain applications bundled in this repository
+var payloadDecoders = []*payloadDecoder{
+	{
+		name: "erc20-bridge",
+		probes: []contractProbe{
+			{metaData: erc20bridge.ERC20BridgeMetaData, method: "CREATE_BRIDGE_TOKENS_REQUIRED_GAS"},
+		},
+		decode: decodeERC20BridgePayload,
+	},
+	{
+		name: "native-token-bridge",
+		probes: []contractProbe{
+			{metaData: nativetokendestination.NativeTokenDestinationMetaData, method: "SOURCE_CHAIN_BURN_ADDRESS"},
+			{metaData: nativetokensource.NativeTokenSourceMetaData, method: "MINT_NATIVE_TOKENS_REQUIRED_GAS"},
+			{metaData: erc20tokensource.ERC20TokenSourceMetaData, method: "MINT_NATIVE_TOKENS_REQUIRED_GAS"},
+		},
+		decode: decodeNativeTokenBridgePayload,
+	},
+	{
+		name: "example-messenger",
+		probes: []contractProbe{
+			{
+				metaData: examplecrosschainmessenger.ExampleCrossChainMessengerMetaData,
+				method:   "getCurrentMessage",
+				args:     []interface{}{[32]byte{}},
+			},
+		},
+		decode: decodeExampleMessengerPayload,
+	},
+	{
+		name: "block-hash-publisher",
+		probes: []contractProbe{
+			{metaData: blockhashreceiver.BlockHashReceiverMetaData, method: "getLatestBlockInfo"},
+		},
+		decode: decodeBlockHashPublisherPayload,
+	},
+}
+
+// payloadDecoderNames returns the values accepted by --payload-decoder
+func payloadDecoderNames() []string {
+	names := []string{autoPayloadDecoder, noPayloadDecoder}
+	for _, decoder := range payloadDecoders {
+		names = append(names, decoder.name)
+	}
+	return names
+}
+
+func getPayloadDecoder(name string) (*payloadDecoder, error) {
+	for _, decoder := range payloadDecoders {
+		if decoder.name == name {
+			return decoder, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown payload decoder %s, must be one of %s",
+		name, strings.Join(payloadDecoderNames(), ", "))
+}
+
+// decodePayload decodes the payload of a message sent to the destination address with the named decoder.
+// With the auto decoder, the decoder is detected by probing the destination contract's ABI if a caller for
+// the destination chain is provided, and otherwise by the decoders that decode the payload unambiguously.
+// A nil output is returned if the payload could not be attributed to a single decoder.
+func decodePayload(
+	ctx context.Context,
+	name string,
+	caller interfaces.ContractCaller,
+	destinationAddress common.Address,
+	payload []byte,
+) (*payloadOutput, error) {
+	switch name {
+	case noPayloadDecoder:
+		return nil, nil
+	case autoPayloadDecoder:
+		if caller != nil {
+			decoder, err := detectPayloadDecoder(ctx, caller, destinationAddress)
+			if err != nil {
+				return nil, err
+			}
+			if decoder == nil {
+				logger.Info("Destination contract does not match a known application",
+					zap.String("destinationAddress", destinationAddress.Hex()))
+				return nil, nil
+			}
+			return decoder.decode(payload)
+		}
+		return detectPayload(payload), nil
+	default:
+		decoder, err := getPayloadDecoder(name)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.decode(payload)
+	}
+}
+
+// detectPayloadDecoder returns the decoder of the application whose contract ABI matches the contract at
+// the address, or nil if none match. A contract matches if the call to any of the decoder's probes succeeds.
+func detectPayloadDecoder(
+	ctx context.Context,
+	caller interfaces.ContractCaller,
+	address common.Address,
+) (*payloadDecoder, error) {
+	for _, decoder := range payloadDecoders {
+		for _, probe := range decoder.probes {
+			contractABI, err := probe.metaData.GetAbi()
+			if err != nil {
+				return nil, err
+			}
+			input, err := contractABI.Pack(probe.method, probe.args...)
+			if err != nil {
+				return nil, err
+			}
+			result, err := caller.CallContract(ctx, interfaces.CallMsg{To: &address, Data: input}, nil)
+			if err != nil || len(result) == 0 {
+				continue
+			}
+			if _, err := contractABI.Unpack(probe.method, result); err != nil {
+				continue
+			}
+			return decoder, nil
+		}
+	}
+	return nil, nil
+}
+
+// detectPayload decodes the payload with every decoder, and returns the output if exactly one of them decodes
+// it. The payloads of some applications share an encoding, in which case the decoder must be provided.
+func detectPayload(payload []byte) *payloadOutput {
+	var (
+		outs    []*payloadOutput
+		matches []string
+	)
+	for _, decoder := range payloadDecoders {
+		out, err := decoder.decode(payload)
+		if err != nil {
+			continue
+		}
+		outs = append(outs, out)
+		matches = append(matches, decoder.name)
+	}
+	if len(outs) > 1 {
+		logger.Info("Payload matches the encoding of several applications, use --payload-decoder or --destination-rpc",
+			zap.Strings("decoders", matches))
+	}
+	if len(outs) != 1 {
+		return nil
+	}
+	return outs[0]
+}
+
+func newABIArguments(types ...string) abi.Arguments {
+	args := make(abi.Arguments, 0, len(types))
+	for _, t := range types {
+		typ, err := abi.NewType(t, "", nil)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create %s ABI type: %v", t, err))
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	return args
+}
+
+// unpackStrict unpacks the ABI encoded values, and checks that packing them again gives back the input,
+// so that payloads of other applications that happen to unpack are rejected.
+func unpackStrict(args abi.Arguments, data []byte) ([]interface{}, error) {
+	values, err := args.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	packed, err := args.Pack(values...)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(packed, data) {
+		return nil, fmt.Errorf("payload is not canonically encoded")
+	}
+	return values, nil
+}
+
+var (
+	erc20BridgeMessageArgs  = newABIArguments("uint8", "bytes")
+	erc20BridgeCreateArgs   = newABIArguments("address", "string", "string", "uint8")
+	erc20BridgeMintArgs     = newABIArguments("address", "address", "uint256")
+	erc20BridgeTransferArgs = newABIArguments("bytes32", "address", "address", "address", "uint256", "uint256")
+	nativeTokenBridgeArgs   = newABIArguments("address", "uint256")
+	exampleMessengerArgs    = newABIArguments("string")
+	blockHashPublisherArgs  = newABIArguments("uint256", "bytes32")
+)
+
+// decodeERC20BridgePayload decodes the messages encoded by the ERC20Bridge's encodeCreateBridgeTokenData,
+// encodeMintBridgeTokensData and encodeTransferBridgeTokensData functions.
+func decodeERC20BridgePayload(payload []byte) (*payloadOutput, error) {
+	values, err := unpackStrict(erc20BridgeMessageArgs, payload)
+	if err != nil {
+		return nil, err
+	}
+	action, actionData := values[0].(uint8), values[1].([]byte)
+	if int(action) >= len(erc20BridgeActions) {
+		return nil, fmt.Errorf("invalid bridge action %d", action)
+	}
+	out := &payloadOutput{
+		Decoder: "erc20-bridge",
+		Action:  erc20BridgeActions[action],
+	}
+	switch out.Action {
+	case "create":
+		values, err := unpackStrict(erc20BridgeCreateArgs, actionData)
+		if err != nil {
+			return nil, err
+		}
+		out.Fields = map[string]string{
+			"nativeContractAddress": values[0].(common.Address).Hex(),
+			"nativeName":            values[1].(string),
+			"nativeSymbol":          values[2].(string),
+			"nativeDecimals":        fmt.Sprint(values[3].(uint8)),
+		}
+	case "mint":
+		values, err := unpackStrict(erc20BridgeMintArgs, actionData)
+		if err != nil {
+			return nil, err
+		}
+		out.Fields = map[string]string{
+			"nativeContractAddress": values[0].(common.Address).Hex(),
+			"recipient":             values[1].(common.Address).Hex(),
+			"amount":                bigIntOutput(values[2].(*big.Int)),
+		}
+	case "transfer":
+		values, err := unpackStrict(erc20BridgeTransferArgs, actionData)
+		if err != nil {
+			return nil, err
+		}
+		out.Fields = map[string]string{
+			"destinationBlockchainID":  ids.ID(values[0].([32]byte)).String(),
+			"destinationBridgeAddress": values[1].(common.Address).Hex(),
+			"nativeContractAddress":    values[2].(common.Address).Hex(),
+			"recipient":                values[3].(common.Address).Hex(),
+			"totalAmount":              bigIntOutput(values[4].(*big.Int)),
+			"secondaryFeeAmount":       bigIntOutput(values[5].(*big.Int)),
+		}
+	}
+	return out, nil
+}
+
+// decodeNativeTokenBridgePayload decodes the transfers sent by the NativeTokenBridge contracts, and the
+// reports of burned transaction fees sent by the NativeTokenDestination to the SOURCE_CHAIN_BURN_ADDRESS.
+func decodeNativeTokenBridgePayload(payload []byte) (*payloadOutput, error) {
+	values, err := unpackStrict(nativeTokenBridgeArgs, payload)
+	if err != nil {
+		return nil, err
+	}
+	recipient, amount := values[0].(common.Address), values[1].(*big.Int)
+	action := "transfer"
+	if recipient == sourceChainBurnAddress {
+		action = "report-burned-tx-fees"
+	}
+	return &payloadOutput{
+		Decoder: "native-token-bridge",
+		Action:  action,
+		Fields: map[string]string{
+			"recipient": recipient.Hex(),
+			"amount":    bigIntOutput(amount),
+		},
+	}, nil
+}
+
+// decodeExampleMessengerPayload decodes the strings sent by the ExampleCrossChainMessenger
+func decodeExampleMessengerPayload(payload []byte) (*payloadOutput, error) {
+	values, err := unpackStrict(exampleMessengerArgs, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &payloadOutput{
+		Decoder: "example-messenger",
+		Action:  "send-message",
+		Fields: map[string]string{
+			"message": values[0].(string),
+		},
+	}, nil
+}
+
+// decodeBlockHashPublisherPayload decodes the block hashes published by the BlockHashPublisher
+func decodeBlockHashPublisherPayload(payload []byte) (*payloadOutput, error) {
+	values, err := unpackStrict(blockHashPublisherArgs, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &payloadOutput{
+		Decoder: "block-hash-publisher",
+		Action:  "publish-block-hash",
+		Fields: map[string]string{
+			"blockHeight": bigIntOutput(values[0].(*big.Int)),
+			"blockHash":   common.Hash(values[1].([32]byte)).Hex(),
+		},
+	}, nil
+}
@@ -0,0 +1,243 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	blockhashreceiver "github.com/ava-labs/teleporter/abi-bindings/go/CrossChainApplications/examples/VerifiedBlockHash/BlockHashReceiver"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func packTestPayload(t *testing.T, types []string, values ...interface{}) []byte {
+	b, err := newABIArguments(types...).Pack(values...)
+	require.NoError(t, err)
+	return b
+}
+
+func packTestERC20BridgePayload(t *testing.T, action uint8, types []string, values ...interface{}) []byte {
+	return packTestPayload(t, []string{"uint8", "bytes"}, action, packTestPayload(t, types, values...))
+}
+
+func TestDecodePayload(t *testing.T) {
+	nativeToken := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	recipient := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	bridge := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	blockchainID := ids.ID{1, 2, 3}
+	blockHash := common.HexToHash("0xabcdef")
+
+	var tests = []struct {
+		name     string
+		decoder  string
+		payload  []byte
+		expected *payloadOutput
+		err      string
+	}{
+		{
+			name:    "erc20 bridge create",
+			decoder: autoPayloadDecoder,
+			payload: packTestERC20BridgePayload(t, 0, []string{"address", "string", "string", "uint8"},
+				nativeToken, "Token", "TOK", uint8(18)),
+			expected: &payloadOutput{
+				Decoder: "erc20-bridge",
+				Action:  "create",
+				Fields: map[string]string{
+					"nativeContractAddress": nativeToken.Hex(),
+					"nativeName":            "Token",
+					"nativeSymbol":          "TOK",
+					"nativeDecimals":        "18",
+				},
+			},
+		},
+		{
+			name:    "erc20 bridge mint",
+			decoder: autoPayloadDecoder,
+			payload: packTestERC20BridgePayload(t, 1, []string{"address", "address", "uint256"},
+				nativeToken, recipient, big.NewInt(100)),
+			expected: &payloadOutput{
+				Decoder: "erc20-bridge",
+				Action:  "mint",
+				Fields: map[string]string{
+					"nativeContractAddress": nativeToken.Hex(),
+					"recipient":             recipient.Hex(),
+					"amount":                "100",
+				},
+			},
+		},
+		{
+			name:    "erc20 bridge transfer",
+			decoder: "erc20-bridge",
+			payload: packTestERC20BridgePayload(t, 2,
+				[]string{"bytes32", "address", "address", "address", "uint256", "uint256"},
+				blockchainID, bridge, nativeToken, recipient, big.NewInt(100), big.NewInt(5)),
+			expected: &payloadOutput{
+				Decoder: "erc20-bridge",
+				Action:  "transfer",
+				Fields: map[string]string{
+					"destinationBlockchainID":  blockchainID.String(),
+					"destinationBridgeAddress": bridge.Hex(),
+					"nativeContractAddress":    nativeToken.Hex(),
+					"recipient":                recipient.Hex(),
+					"totalAmount":              "100",
+					"secondaryFeeAmount":       "5",
+				},
+			},
+		},
+		{
+			name:    "erc20 bridge invalid action",
+			decoder: "erc20-bridge",
+			payload: packTestERC20BridgePayload(t, 3, []string{"uint256"}, big.NewInt(1)),
+			err:     "invalid bridge action 3",
+		},
+		{
+			name:    "example messenger",
+			decoder: autoPayloadDecoder,
+			payload: packTestPayload(t, []string{"string"}, "hello world"),
+			expected: &payloadOutput{
+				Decoder: "example-messenger",
+				Action:  "send-message",
+				Fields:  map[string]string{"message": "hello world"},
+			},
+		},
+		{
+			name:    "native token bridge transfer",
+			decoder: "native-token-bridge",
+			payload: packTestPayload(t, []string{"address", "uint256"}, recipient, big.NewInt(7)),
+			expected: &payloadOutput{
+				Decoder: "native-token-bridge",
+				Action:  "transfer",
+				Fields:  map[string]string{"recipient": recipient.Hex(), "amount": "7"},
+			},
+		},
+		{
+			name:    "native token bridge burn report",
+			decoder: "native-token-bridge",
+			payload: packTestPayload(t, []string{"address", "uint256"}, sourceChainBurnAddress, big.NewInt(7)),
+			expected: &payloadOutput{
+				Decoder: "native-token-bridge",
+				Action:  "report-burned-tx-fees",
+				Fields:  map[string]string{"recipient": sourceChainBurnAddress.Hex(), "amount": "7"},
+			},
+		},
+		{
+			name:    "block hash publisher",
+			decoder: "block-hash-publisher",
+			payload: packTestPayload(t, []string{"uint256", "bytes32"}, big.NewInt(42), blockHash),
+			expected: &payloadOutput{
+				Decoder: "block-hash-publisher",
+				Action:  "publish-block-hash",
+				Fields:  map[string]string{"blockHeight": "42", "blockHash": blockHash.Hex()},
+			},
+		},
+		{
+			name:     "ambiguous encoding",
+			decoder:  autoPayloadDecoder,
+			payload:  packTestPayload(t, []string{"uint256", "bytes32"}, big.NewInt(42), blockHash),
+			expected: nil,
+		},
+		{
+			name:     "unknown payload",
+			decoder:  autoPayloadDecoder,
+			payload:  []byte{1, 2, 3, 4},
+			expected: nil,
+		},
+		{
+			name:    "mismatched decoder",
+			decoder: "example-messenger",
+			payload: []byte{1, 2, 3, 4},
+			err:     "abi",
+		},
+		{
+			name:     "no decoder",
+			decoder:  noPayloadDecoder,
+			payload:  packTestPayload(t, []string{"string"}, "hello world"),
+			expected: nil,
+		},
+		{
+			name:    "unknown decoder",
+			decoder: "foo",
+			err:     "unknown payload decoder foo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := decodePayload(context.Background(), tt.decoder, nil, common.Address{}, tt.payload)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+// fakeContractCaller succeeds calls to the contract at address with the given input, and reverts otherwise
+type fakeContractCaller struct {
+	address common.Address
+	input   []byte
+	output  []byte
+}
+
+func (c *fakeContractCaller) CallContract(_ context.Context, msg interfaces.CallMsg, _ *big.Int) ([]byte, error) {
+	if *msg.To != c.address || hex.EncodeToString(msg.Data) != hex.EncodeToString(c.input) {
+		return nil, fmt.Errorf("execution reverted")
+	}
+	return c.output, nil
+}
+
+func TestDetectPayloadDecoder(t *testing.T) {
+	receiverABI, err := blockhashreceiver.BlockHashReceiverMetaData.GetAbi()
+	require.NoError(t, err)
+	input, err := receiverABI.Pack("getLatestBlockInfo")
+	require.NoError(t, err)
+	output, err := receiverABI.Methods["getLatestBlockInfo"].Outputs.Pack(big.NewInt(1), [32]byte{})
+	require.NoError(t, err)
+
+	receiver := common.HexToAddress("0x1234")
+	caller := &fakeContractCaller{address: receiver, input: input, output: output}
+
+	decoder, err := detectPayloadDecoder(context.Background(), caller, receiver)
+	require.NoError(t, err)
+	require.Equal(t, "block-hash-publisher", decoder.name)
+
+	decoder, err = detectPayloadDecoder(context.Background(), caller, common.HexToAddress("0x5678"))
+	require.NoError(t, err)
+	require.Nil(t, decoder)
+
+	// The payload is ambiguous without the destination chain, but is decoded by the detected decoder
+	payload := packTestPayload(t, []string{"uint256", "bytes32"}, big.NewInt(42), common.Hash{})
+	out, err := decodePayload(context.Background(), autoPayloadDecoder, caller, receiver, payload)
+	require.NoError(t, err)
+	require.Equal(t, "publish-block-hash", out.Action)
+}
+
+func TestMessageCmdPayload(t *testing.T) {
+	t.Cleanup(func() { outputFormat = tableOutput })
+
+	message := createTestTeleporterMessage()
+	message.Message = packTestPayload(t, []string{"string"}, "hello world")
+	b, err := teleportermessenger.PackTeleporterMessage(message)
+	require.NoError(t, err)
+
+	out, err := executeTestCmd(t, rootCmd, "message", hex.EncodeToString(b), "--output", jsonOutput)
+	require.NoError(t, err)
+	var messageOut teleporterMessageOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &messageOut))
+	require.Equal(t, &payloadOutput{
+		Decoder: "example-messenger",
+		Action:  "send-message",
+		Fields:  map[string]string{"message": "hello world"},
+	}, messageOut.Payload)
+}
@@ -0,0 +1,295 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	teleporterUtils "github.com/ava-labs/teleporter/utils/teleporter-utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const defaultReceiptsBatchSize = 20
+
+var (
+	receiptsBatchSize               int
+	receiptsFeeTokenAddress         string
+	receiptsFeeAmount               string
+	receiptsAllowedRelayerAddresses []string
+)
+
+type receiptQueueEntry struct {
+	receipt   teleportermessenger.TeleporterMessageReceipt
+	messageID ids.ID
+}
+
+type receiptQueueEntryOutput struct {
+	Index                int    `json:"index" yaml:"index"`
+	MessageID            string `json:"messageID" yaml:"messageID"`
+	ReceivedMessageNonce string `json:"receivedMessageNonce" yaml:"receivedMessageNonce"`
+	RelayerRewardAddress string `json:"relayerRewardAddress" yaml:"relayerRewardAddress"`
+}
+
+type receiptQueueOutput struct {
+	SourceBlockchainID string                    `json:"sourceBlockchainID" yaml:"sourceBlockchainID"`
+	Size               int                       `json:"size" yaml:"size"`
+	Receipts           []receiptQueueEntryOutput `json:"receipts" yaml:"receipts"`
+}
+
+type receiptsFlushOutput struct {
+	TransactionHash string   `json:"transactionHash" yaml:"transactionHash"`
+	MessageID       string   `json:"messageID" yaml:"messageID"`
+	ReceiptIDs      []string `json:"receiptIDs" yaml:"receiptIDs"`
+}
+
+var receiptsCmd = &cobra.Command{
+	Use:   "receipts",
+	Short: "Inspects and flushes the outstanding receipt queue",
+	Long: `Inspects and flushes the queue of receipts for messages received from a source
+chain that have not yet been sent back to the source chain. Receipts are normally
+returned in batches attached to messages sent back to the source chain, and relayers
+are only able to redeem their rewards once the receipts of the messages they
+delivered have been returned.`,
+	Args: cobra.NoArgs,
+}
+
+var receiptsListCmd = &cobra.Command{
+	Use:   "list --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS --source-blockchain-id BLOCKCHAIN_ID",
+	Short: "Lists the outstanding receipt queue for a source chain",
+	Long: `Lists the receipts in the outstanding receipt queue of the chain connected to by
+--rpc for messages received from the source chain, using getReceiptQueueSize and
+getReceiptAtIndex. The ID of the message each receipt is for is also printed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceBlockchainID, err := parseID(sourceBlockchainIDArg)
+		cobra.CheckErr(err)
+
+		entries, err := getReceiptQueue(context.Background(), sourceBlockchainID)
+		cobra.CheckErr(err)
+
+		out := newReceiptQueueOutput(sourceBlockchainID, entries)
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, out))
+			return
+		}
+		for _, receipt := range out.Receipts {
+			logger.Info("Outstanding receipt",
+				zap.Int("index", receipt.Index),
+				zap.String("messageID", receipt.MessageID),
+				zap.String("receivedMessageNonce", receipt.ReceivedMessageNonce),
+				zap.String("relayerRewardAddress", receipt.RelayerRewardAddress))
+		}
+		cmd.Printf("Receipt queue size: %d\n", out.Size)
+		cmd.Println("Receipts list command ran successfully")
+	},
+}
+
+var receiptsFlushCmd = &cobra.Command{
+	Use:   "flush --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS --source-blockchain-id BLOCKCHAIN_ID",
+	Short: "Sends the outstanding receipts back to the source chain",
+	Long: `Sends the receipts in the outstanding receipt queue back to the source chain by
+batching their message IDs into sendSpecifiedReceipts calls of at most --batch-size
+receipts each. Each call sends a new message to the source chain, which must be
+relayed like any other message, optionally with a fee. Note that sendSpecifiedReceipts
+does not remove the receipts from the queue, so they are also sent again with later
+messages. This is harmless, as receipts are only processed once.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceBlockchainID, err := parseID(sourceBlockchainIDArg)
+		cobra.CheckErr(err)
+		if receiptsBatchSize <= 0 {
+			cobra.CheckErr(fmt.Errorf("batch size must be positive"))
+		}
+		feeTokenAddress, err := parseAddress(receiptsFeeTokenAddress)
+		cobra.CheckErr(err)
+		feeAmount, err := parseBigInt(receiptsFeeAmount)
+		cobra.CheckErr(err)
+		feeInfo := teleportermessenger.TeleporterFeeInfo{
+			FeeTokenAddress: feeTokenAddress,
+			Amount:          feeAmount,
+		}
+		allowedRelayerAddresses, err := parseAddresses(receiptsAllowedRelayerAddresses)
+		cobra.CheckErr(err)
+
+		ctx := context.Background()
+		entries, err := getReceiptQueue(ctx, sourceBlockchainID)
+		cobra.CheckErr(err)
+		messageIDs := make([][32]byte, 0, len(entries))
+		for _, entry := range entries {
+			messageIDs = append(messageIDs, entry.messageID)
+		}
+
+		chainID, err := client.ChainID(ctx)
+		cobra.CheckErr(err)
+		opts, err := newTransactOpts(ctx, &signerKeyFlags, chainID)
+		cobra.CheckErr(err)
+
+		flushOuts := []receiptsFlushOutput{}
+		for _, batch := range batchMessageIDs(messageIDs, receiptsBatchSize) {
+			logger.Info("Sending specified receipts",
+				zap.String("sourceBlockchainID", sourceBlockchainID.String()),
+				zap.Int("count", len(batch)))
+			flushOut, err := sendSpecifiedReceipts(
+				ctx, client, opts, teleporterAddress, sourceBlockchainID, batch, feeInfo, allowedRelayerAddresses)
+			cobra.CheckErr(err)
+			flushOuts = append(flushOuts, flushOut)
+		}
+
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, flushOuts))
+			return
+		}
+		for _, flushOut := range flushOuts {
+			logger.Info("Sent specified receipts",
+				zap.String("txHash", flushOut.TransactionHash),
+				zap.String("messageID", flushOut.MessageID),
+				zap.Strings("receiptIDs", flushOut.ReceiptIDs))
+		}
+		cmd.Printf("Sent %d receipts in %d messages\n", len(messageIDs), len(flushOuts))
+		cmd.Println("Receipts flush command ran successfully")
+	},
+}
+
+// getReceiptQueue reads the outstanding receipt queue for the source chain,
+// and calculates the ID of the message each receipt is for.
+func getReceiptQueue(ctx context.Context, sourceBlockchainID ids.ID) ([]receiptQueueEntry, error) {
+	teleporter, err := teleportermessenger.NewTeleporterMessengerCaller(teleporterAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	blockchainID, err := teleporter.BlockchainID(opts)
+	if err != nil {
+		return nil, err
+	}
+	size, err := teleporter.GetReceiptQueueSize(opts, sourceBlockchainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []receiptQueueEntry
+	for i := big.NewInt(0); i.Cmp(size) < 0; i.Add(i, big.NewInt(1)) {
+		receipt, err := teleporter.GetReceiptAtIndex(opts, sourceBlockchainID, i)
+		if err != nil {
+			return nil, err
+		}
+		messageID, err := teleporterUtils.CalculateMessageID(
+			teleporterAddress, sourceBlockchainID, blockchainID, receipt.ReceivedMessageNonce)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, receiptQueueEntry{
+			receipt:   receipt,
+			messageID: messageID,
+		})
+	}
+	return entries, nil
+}
+
+// sendSpecifiedReceipts approves the fee amount if necessary, and sends a message back to the
+// source chain containing the receipts for the specified message IDs.
+func sendSpecifiedReceipts(
+	ctx context.Context,
+	backend contractBackend,
+	opts *bind.TransactOpts,
+	teleporterAddress common.Address,
+	sourceBlockchainID ids.ID,
+	messageIDs [][32]byte,
+	feeInfo teleportermessenger.TeleporterFeeInfo,
+	allowedRelayerAddresses []common.Address,
+) (receiptsFlushOutput, error) {
+	if feeInfo.Amount.Sign() > 0 {
+		err := ensureERC20Allowance(ctx, backend, opts, feeInfo.FeeTokenAddress, teleporterAddress, feeInfo.Amount)
+		if err != nil {
+			return receiptsFlushOutput{}, err
+		}
+	}
+
+	teleporter, err := teleportermessenger.NewTeleporterMessenger(teleporterAddress, backend)
+	if err != nil {
+		return receiptsFlushOutput{}, err
+	}
+	tx, err := teleporter.SendSpecifiedReceipts(opts, sourceBlockchainID, messageIDs, feeInfo, allowedRelayerAddresses)
+	if err != nil {
+		return receiptsFlushOutput{}, err
+	}
+	receipt, err := waitForSuccess(ctx, backend, tx)
+	if err != nil {
+		return receiptsFlushOutput{}, err
+	}
+	event, err := findEvent(receipt, teleporterAddress, teleporter.ParseSendCrossChainMessage)
+	if err != nil {
+		return receiptsFlushOutput{}, err
+	}
+
+	out := receiptsFlushOutput{
+		TransactionHash: receipt.TxHash.Hex(),
+		MessageID:       ids.ID(event.MessageID).String(),
+		ReceiptIDs:      []string{},
+	}
+	for _, messageID := range messageIDs {
+		out.ReceiptIDs = append(out.ReceiptIDs, ids.ID(messageID).String())
+	}
+	return out, nil
+}
+
+// batchMessageIDs splits the message IDs into batches of at most size message IDs
+func batchMessageIDs(messageIDs [][32]byte, size int) [][][32]byte {
+	var batches [][][32]byte
+	for len(messageIDs) > size {
+		batches = append(batches, messageIDs[:size])
+		messageIDs = messageIDs[size:]
+	}
+	if len(messageIDs) > 0 {
+		batches = append(batches, messageIDs)
+	}
+	return batches
+}
+
+func newReceiptQueueOutput(sourceBlockchainID ids.ID, entries []receiptQueueEntry) receiptQueueOutput {
+	out := receiptQueueOutput{
+		SourceBlockchainID: sourceBlockchainID.String(),
+		Size:               len(entries),
+		Receipts:           []receiptQueueEntryOutput{},
+	}
+	for i, entry := range entries {
+		out.Receipts = append(out.Receipts, receiptQueueEntryOutput{
+			Index:                i,
+			MessageID:            entry.messageID.String(),
+			ReceivedMessageNonce: entry.receipt.ReceivedMessageNonce.String(),
+			RelayerRewardAddress: entry.receipt.RelayerRewardAddress.Hex(),
+		})
+	}
+	return out
+}
+
+func init() {
+	rootCmd.AddCommand(receiptsCmd)
+	receiptsCmd.AddCommand(receiptsListCmd, receiptsFlushCmd)
+
+	for _, cmd := range []*cobra.Command{receiptsListCmd, receiptsFlushCmd} {
+		addRPCFlags(cmd)
+		cmd.Flags().StringVar(&sourceBlockchainIDArg, "source-blockchain-id", "",
+			"Blockchain ID of the chain the messages were received from, CB58 or hex encoded")
+		err := cmd.MarkFlagRequired("source-blockchain-id")
+		cobra.CheckErr(err)
+	}
+
+	addKeyFlags(receiptsFlushCmd, &signerKeyFlags)
+	receiptsFlushCmd.Flags().IntVar(&receiptsBatchSize, "batch-size", defaultReceiptsBatchSize,
+		"Maximum number of receipts to send in a single sendSpecifiedReceipts call")
+	receiptsFlushCmd.Flags().StringVar(&receiptsFeeTokenAddress, "fee-token", "",
+		"Address of the ERC20 token to pay the relayer fee of each receipts message in")
+	receiptsFlushCmd.Flags().StringVar(&receiptsFeeAmount, "fee-amount", "0",
+		"Relayer fee amount of each receipts message")
+	receiptsFlushCmd.Flags().StringSliceVar(&receiptsAllowedRelayerAddresses, "allowed-relayers", nil,
+		"Addresses of the relayers allowed to deliver the receipts messages")
+}
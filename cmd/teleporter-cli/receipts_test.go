@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	teleporterUtils "github.com/ava-labs/teleporter/utils/teleporter-utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptsCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "help",
+			args: []string{"receipts", "--help"},
+			err:  nil,
+			out:  "Inspects and flushes the queue of receipts for messages received from a source",
+		},
+		{
+			name: "list args",
+			args: []string{"receipts", "list", "arg"},
+			err:  fmt.Errorf("unknown command \"arg\" for \"teleporter-cli receipts list\""),
+		},
+		{
+			name: "list help",
+			args: []string{"receipts", "list", "--help"},
+			err:  nil,
+			out:  "Lists the receipts in the outstanding receipt queue",
+		},
+		{
+			name: "flush help",
+			args: []string{"receipts", "flush", "--help"},
+			err:  nil,
+			out:  "Sends the receipts in the outstanding receipt queue back to the source chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestReceiptsListCmd(t *testing.T) {
+	teleporterAddress := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	relayer := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	sourceBlockchainID := ids.ID{1}
+	blockchainID := ids.ID{2}
+	receipts := []teleportermessenger.TeleporterMessageReceipt{
+		{ReceivedMessageNonce: big.NewInt(7), RelayerRewardAddress: relayer},
+		{ReceivedMessageNonce: big.NewInt(8), RelayerRewardAddress: relayer},
+	}
+	url := newTestRPCServer(t, map[string]func(args []interface{}) []interface{}{
+		"blockchainID": func([]interface{}) []interface{} {
+			return []interface{}{[32]byte(blockchainID)}
+		},
+		"getReceiptQueueSize": func([]interface{}) []interface{} {
+			return []interface{}{big.NewInt(int64(len(receipts)))}
+		},
+		"getReceiptAtIndex": func(args []interface{}) []interface{} {
+			return []interface{}{receipts[args[1].(*big.Int).Int64()]}
+		},
+	})
+	messageID, err := teleporterUtils.CalculateMessageID(
+		teleporterAddress, sourceBlockchainID, blockchainID, receipts[1].ReceivedMessageNonce)
+	require.NoError(t, err)
+	args := []string{"receipts", "list", "--rpc", url, "--teleporter-address", teleporterAddress.Hex(),
+		"--source-blockchain-id", sourceBlockchainID.String()}
+
+	var tests = []struct {
+		name   string
+		output string
+		err    error
+		out    string
+	}{
+		{
+			name:   "table",
+			output: tableOutput,
+			out:    "Receipt queue size: 2",
+		},
+		{
+			name:   "json",
+			output: jsonOutput,
+			out:    messageID.String(),
+		},
+		{
+			name:   "invalid output",
+			output: "xml",
+			err:    fmt.Errorf("invalid output format xml"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// The root command's persistent pre-run function sets the logger
+			logger = nil
+			t.Cleanup(func() {
+				logger = logging.NoLog{}
+				outputFormat = tableOutput
+			})
+			out, err := executeTestCmd(t, rootCmd, append(args, "--output", tt.output)...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestBatchMessageIDs(t *testing.T) {
+	messageIDs := make([][32]byte, 5)
+	for i := range messageIDs {
+		messageIDs[i][0] = byte(i)
+	}
+
+	var tests = []struct {
+		name    string
+		size    int
+		lengths []int
+	}{
+		{
+			name:    "single batch",
+			size:    10,
+			lengths: []int{5},
+		},
+		{
+			name:    "exact batches",
+			size:    5,
+			lengths: []int{5},
+		},
+		{
+			name:    "partial last batch",
+			size:    2,
+			lengths: []int{2, 2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := batchMessageIDs(messageIDs, tt.size)
+			require.Len(t, batches, len(tt.lengths))
+			var flattened [][32]byte
+			for i, batch := range batches {
+				require.Len(t, batch, tt.lengths[i])
+				flattened = append(flattened, batch...)
+			}
+			require.Equal(t, messageIDs, flattened)
+		})
+	}
+	require.Empty(t, batchMessageIDs(nil, 2))
+}
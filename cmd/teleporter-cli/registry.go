@@ -0,0 +1,82 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	teleporterregistry "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/upgrades/TeleporterRegistry"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	registryFlag = "registry"
+
+	// registryVersionNotFound is the revert reason of getAddressFromVersion for versions that were never registered
+	registryVersionNotFound = "TeleporterRegistry: version not found"
+)
+
+var (
+	registryAddress   string
+	teleporterVersion uint64
+)
+
+// registryCaller is the subset of the TeleporterRegistry contract's view functions used to resolve
+// TeleporterMessenger addresses. It is implemented by teleporterregistry.TeleporterRegistryCaller.
+type registryCaller interface {
+	LatestVersion(opts *bind.CallOpts) (*big.Int, error)
+	GetLatestTeleporter(opts *bind.CallOpts) (common.Address, error)
+	GetAddressFromVersion(opts *bind.CallOpts, version *big.Int) (common.Address, error)
+}
+
+func newRegistryCaller(address common.Address, caller bind.ContractCaller) (registryCaller, error) {
+	return teleporterregistry.NewTeleporterRegistryCaller(address, caller)
+}
+
+// resolveTeleporterAddress returns the address of the given TeleporterMessenger version registered in
+// the registry, or the address of the latest version if version is zero.
+func resolveTeleporterAddress(ctx context.Context, registry registryCaller, version uint64) (common.Address, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	if version == 0 {
+		address, err := registry.GetLatestTeleporter(opts)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("failed to get latest Teleporter address from registry: %w", err)
+		}
+		return address, nil
+	}
+	address, err := registry.GetAddressFromVersion(opts, new(big.Int).SetUint64(version))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get Teleporter version %d from registry: %w", version, err)
+	}
+	return address, nil
+}
+
+// registeredTeleporterVersions returns the address of every TeleporterMessenger version registered in the
+// registry, mapped to its version. Versions may be skipped when registering, so versions that are not
+// found are ignored.
+func registeredTeleporterVersions(ctx context.Context, registry registryCaller) (map[common.Address]*big.Int, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	latestVersion, err := registry.LatestVersion(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest version from registry: %w", err)
+	}
+	versions := make(map[common.Address]*big.Int)
+	for version := big.NewInt(1); version.Cmp(latestVersion) <= 0; version = new(big.Int).Add(version, common.Big1) {
+		address, err := registry.GetAddressFromVersion(opts, version)
+		if err != nil && strings.Contains(err.Error(), registryVersionNotFound) {
+			logger.Debug("Skipping unregistered Teleporter version", zap.String("version", version.String()))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Teleporter version %s from registry: %w", version, err)
+		}
+		versions[address] = version
+	}
+	return versions, nil
+}
@@ -0,0 +1,131 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is a registryCaller backed by a map of versions to addresses
+type fakeRegistry struct {
+	latestVersion *big.Int
+	addresses     map[uint64]common.Address
+	err           error
+}
+
+func (r *fakeRegistry) LatestVersion(*bind.CallOpts) (*big.Int, error) {
+	return r.latestVersion, r.err
+}
+
+func (r *fakeRegistry) GetLatestTeleporter(opts *bind.CallOpts) (common.Address, error) {
+	return r.GetAddressFromVersion(opts, r.latestVersion)
+}
+
+func (r *fakeRegistry) GetAddressFromVersion(_ *bind.CallOpts, version *big.Int) (common.Address, error) {
+	if r.err != nil {
+		return common.Address{}, r.err
+	}
+	address, ok := r.addresses[version.Uint64()]
+	if !ok {
+		return common.Address{}, fmt.Errorf("execution reverted: %s", registryVersionNotFound)
+	}
+	return address, nil
+}
+
+func TestResolveTeleporterAddress(t *testing.T) {
+	registry := &fakeRegistry{
+		latestVersion: big.NewInt(3),
+		addresses: map[uint64]common.Address{
+			1: common.HexToAddress("0x1"),
+			3: common.HexToAddress("0x3"),
+		},
+	}
+	var tests = []struct {
+		name     string
+		version  uint64
+		expected common.Address
+		err      string
+	}{
+		{
+			name:     "latest",
+			version:  0,
+			expected: common.HexToAddress("0x3"),
+		},
+		{
+			name:     "version",
+			version:  1,
+			expected: common.HexToAddress("0x1"),
+		},
+		{
+			name:    "version not found",
+			version: 2,
+			err:     "failed to get Teleporter version 2 from registry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := resolveTeleporterAddress(context.Background(), registry, tt.version)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, address)
+		})
+	}
+}
+
+func TestRegisteredTeleporterVersions(t *testing.T) {
+	var tests = []struct {
+		name     string
+		registry *fakeRegistry
+		expected map[common.Address]*big.Int
+		err      string
+	}{
+		{
+			name:     "empty registry",
+			registry: &fakeRegistry{latestVersion: big.NewInt(0)},
+			expected: map[common.Address]*big.Int{},
+		},
+		{
+			name: "skipped versions",
+			registry: &fakeRegistry{
+				latestVersion: big.NewInt(4),
+				addresses: map[uint64]common.Address{
+					1: common.HexToAddress("0x1"),
+					4: common.HexToAddress("0x4"),
+				},
+			},
+			expected: map[common.Address]*big.Int{
+				common.HexToAddress("0x1"): big.NewInt(1),
+				common.HexToAddress("0x4"): big.NewInt(4),
+			},
+		},
+		{
+			name:     "call error",
+			registry: &fakeRegistry{latestVersion: big.NewInt(1), err: fmt.Errorf("connection refused")},
+			err:      "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, err := registeredTeleporterVersions(context.Background(), tt.registry)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, versions)
+		})
+	}
+}
@@ -0,0 +1,317 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	predicateutils "github.com/ava-labs/subnet-evm/predicate"
+	warpBackend "github.com/ava-labs/subnet-evm/warp"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	gasUtils "github.com/ava-labs/teleporter/utils/gas-utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	sourceNodeURI      string
+	signingSubnetID    string
+	quorumNumerator    uint64
+	relayRewardAddress string
+	dryRun             bool
+)
+
+type relayOutput struct {
+	WarpMessageID     string `json:"warpMessageID" yaml:"warpMessageID"`
+	MessageID         string `json:"messageID" yaml:"messageID"`
+	Skipped           string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	NumSigners        int    `json:"numSigners,omitempty" yaml:"numSigners,omitempty"`
+	GasLimit          uint64 `json:"gasLimit,omitempty" yaml:"gasLimit,omitempty"`
+	TransactionHash   string `json:"transactionHash,omitempty" yaml:"transactionHash,omitempty"`
+	SignedTransaction string `json:"signedTransaction,omitempty" yaml:"signedTransaction,omitempty"`
+}
+
+var relayCmd = &cobra.Command{
+	Use: "relay --source-rpc RPC_URL --destination-rpc RPC_URL --teleporter-address CONTRACT_ADDRESS " +
+		"[--dry-run] TRANSACTION_HASH",
+	Short: "Manually delivers the Teleporter messages sent in a transaction",
+	Long: `Given the hash of a transaction on the source chain, this command extracts the
+Warp messages containing Teleporter messages from the transaction's logs, fetches
+their aggregate signatures from the source chain's Warp API, and delivers them to
+the destination chain by calling receiveCrossChainMessage with the signed Warp
+message in the transaction's predicate access list. Messages that were already
+received on the destination chain are skipped. With --dry-run, the signed
+transactions are printed instead of being broadcast.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		receipt, err := sourceClient.TransactionReceipt(ctx, common.HexToHash(args[0]))
+		cobra.CheckErr(err)
+
+		key, err := signerKeyFlags.privateKey()
+		cobra.CheckErr(err)
+		rewardAddress := crypto.PubkeyToAddress(key.PublicKey)
+		if relayRewardAddress != "" {
+			rewardAddress, err = parseAddress(relayRewardAddress)
+			cobra.CheckErr(err)
+		}
+
+		nodeURI := sourceNodeURI
+		if nodeURI == "" {
+			nodeURI = nodeURIFromRPC(sourceRPCEndpoint)
+		}
+
+		// The nonce is read once and incremented for each transaction, since the transactions of the earlier
+		// messages may not be accepted yet when the next one is signed
+		nonce, err := destinationClient.NonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey), nil)
+		cobra.CheckErr(err)
+
+		var relayOuts []relayOutput
+		for _, log := range receipt.Logs {
+			if log.Address != warp.ContractAddress {
+				continue
+			}
+			// Warp messages sent by other contracts do not contain a Teleporter message, so they are skipped
+			// before their payload is unpacked
+			unsignedMsg, addressedCall, err := parseAddressedCallWarpLog(*log)
+			cobra.CheckErr(err)
+			if common.BytesToAddress(addressedCall.SourceAddress) != teleporterAddress {
+				logger.Debug("Skipping Warp message not sent by Teleporter",
+					zap.String("warpMessageID", unsignedMsg.ID().String()))
+				continue
+			}
+			teleporterMessage, err := teleportermessenger.UnpackTeleporterMessage(addressedCall.Payload)
+			cobra.CheckErr(err)
+
+			relayOut, err := relayMessage(ctx, nodeURI, unsignedMsg, teleporterMessage, key, rewardAddress, nonce)
+			cobra.CheckErr(err)
+			if relayOut.TransactionHash != "" {
+				nonce++
+			}
+			relayOuts = append(relayOuts, relayOut)
+		}
+		if len(relayOuts) == 0 {
+			cobra.CheckErr(fmt.Errorf("no Teleporter Warp messages found in transaction %s", args[0]))
+		}
+
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, relayOuts))
+			return
+		}
+		for _, relayOut := range relayOuts {
+			switch {
+			case relayOut.Skipped != "":
+				cmd.Printf("Message %s skipped: %s\n", relayOut.MessageID, relayOut.Skipped)
+			case relayOut.SignedTransaction != "":
+				cmd.Printf("Message %s signed transaction %s:\n", relayOut.MessageID, relayOut.TransactionHash)
+				fmt.Fprintln(cmd.OutOrStdout(), relayOut.SignedTransaction)
+			default:
+				cmd.Printf("Message %s delivered in transaction %s\n", relayOut.MessageID, relayOut.TransactionHash)
+			}
+		}
+		cmd.Println("Relay command ran successfully")
+	},
+}
+
+// relayMessage delivers a single Teleporter message to the destination chain, in a transaction with the given nonce
+// if the message has not been received yet
+func relayMessage(
+	ctx context.Context,
+	nodeURI string,
+	unsignedMsg *avalancheWarp.UnsignedMessage,
+	teleporterMessage *teleportermessenger.TeleporterMessage,
+	key *ecdsa.PrivateKey,
+	rewardAddress common.Address,
+	nonce uint64,
+) (relayOutput, error) {
+	messageID, err := teleportermessenger.CalculateTeleporterMessageID(
+		teleporterAddress, unsignedMsg.SourceChainID, *teleporterMessage)
+	if err != nil {
+		return relayOutput{}, err
+	}
+	relayOut := relayOutput{
+		WarpMessageID: unsignedMsg.ID().String(),
+		MessageID:     messageID.String(),
+	}
+
+	destination, err := teleportermessenger.NewTeleporterMessengerCaller(teleporterAddress, destinationClient)
+	if err != nil {
+		return relayOutput{}, err
+	}
+	destinationBlockchainID, err := destination.BlockchainID(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return relayOutput{}, err
+	}
+	if destinationBlockchainID != teleporterMessage.DestinationBlockchainID {
+		relayOut.Skipped = fmt.Sprintf("message is destined for blockchain %s",
+			ids.ID(teleporterMessage.DestinationBlockchainID))
+		return relayOut, nil
+	}
+	received, err := destination.MessageReceived(&bind.CallOpts{Context: ctx}, messageID)
+	if err != nil {
+		return relayOutput{}, err
+	}
+	if received {
+		relayOut.Skipped = "message already received on destination chain"
+		return relayOut, nil
+	}
+
+	logger.Info("Fetching aggregate signature",
+		zap.String("warpMessageID", unsignedMsg.ID().String()),
+		zap.String("nodeURI", nodeURI))
+	signedMsg, err := getSignedMessage(ctx, nodeURI, unsignedMsg)
+	if err != nil {
+		return relayOutput{}, err
+	}
+	numSigners, err := signedMsg.Signature.NumSigners()
+	if err != nil {
+		return relayOutput{}, err
+	}
+	relayOut.NumSigners = numSigners
+
+	tx, err := createReceiveCrossChainMessageTransaction(
+		ctx, destinationClient, signedMsg, teleporterMessage.RequiredGasLimit, key, rewardAddress, nonce)
+	if err != nil {
+		return relayOutput{}, err
+	}
+	relayOut.GasLimit = tx.Gas()
+	relayOut.TransactionHash = tx.Hash().Hex()
+
+	if dryRun {
+		txBytes, err := tx.MarshalBinary()
+		if err != nil {
+			return relayOutput{}, err
+		}
+		relayOut.SignedTransaction = hexutil.Encode(txBytes)
+		return relayOut, nil
+	}
+
+	if err := destinationClient.SendTransaction(ctx, tx); err != nil {
+		return relayOutput{}, err
+	}
+	if _, err := waitForSuccess(ctx, destinationClient, tx); err != nil {
+		return relayOutput{}, err
+	}
+	return relayOut, nil
+}
+
+// getSignedMessage fetches the aggregate signature of the unsigned Warp message from the source chain's Warp API
+func getSignedMessage(
+	ctx context.Context,
+	nodeURI string,
+	unsignedMsg *avalancheWarp.UnsignedMessage,
+) (*avalancheWarp.Message, error) {
+	warpClient, err := warpBackend.NewClient(nodeURI, unsignedMsg.SourceChainID.String())
+	if err != nil {
+		return nil, err
+	}
+	signedMsgBytes, err := warpClient.GetMessageAggregateSignature(
+		ctx, unsignedMsg.ID(), quorumNumerator, signingSubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aggregate signature: %w", err)
+	}
+	return avalancheWarp.ParseMessage(signedMsgBytes)
+}
+
+// createReceiveCrossChainMessageTransaction constructs and signs a transaction calling receiveCrossChainMessage,
+// with the signed Warp message included in the transaction's predicate access list.
+func createReceiveCrossChainMessageTransaction(
+	ctx context.Context,
+	client ethclient.Client,
+	signedMsg *avalancheWarp.Message,
+	requiredGasLimit *big.Int,
+	key *ecdsa.PrivateKey,
+	rewardAddress common.Address,
+	nonce uint64,
+) (*types.Transaction, error) {
+	numSigners, err := signedMsg.Signature.NumSigners()
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := gasUtils.CalculateReceiveMessageGasLimit(numSigners, requiredGasLimit)
+	if err != nil {
+		return nil, err
+	}
+	callData, err := teleportermessenger.PackReceiveCrossChainMessage(0, rewardAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasFeeCap, gasTipCap, err := calculateTxParams(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := predicateutils.NewPredicateTx(
+		chainID,
+		nonce,
+		&teleporterAddress,
+		gasLimit,
+		gasFeeCap,
+		gasTipCap,
+		big.NewInt(0),
+		callData,
+		types.AccessList{},
+		warp.ContractAddress,
+		signedMsg.Bytes(),
+	)
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+}
+
+// calculateTxParams returns the gasFeeCap and gasTipCap to use when constructing a transaction
+func calculateTxParams(ctx context.Context, client ethclient.Client) (*big.Int, *big.Int, error) {
+	baseFee, err := client.EstimateBaseFee(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gasFeeCap := new(big.Int).Mul(baseFee, big.NewInt(gasUtils.BaseFeeFactor))
+	gasFeeCap.Add(gasFeeCap, big.NewInt(gasUtils.MaxPriorityFeePerGas))
+	return gasFeeCap, gasTipCap, nil
+}
+
+// nodeURIFromRPC strips the chain specific path from an RPC endpoint such as
+// http://127.0.0.1:9650/ext/bc/C/rpc, leaving the base URI of the node.
+func nodeURIFromRPC(rpcURL string) string {
+	if i := strings.Index(rpcURL, "/ext/"); i >= 0 {
+		return rpcURL[:i]
+	}
+	return rpcURL
+}
+
+func init() {
+	rootCmd.AddCommand(relayCmd)
+	addSourceDestinationFlags(relayCmd)
+	addKeyFlags(relayCmd, &signerKeyFlags)
+	relayCmd.Flags().StringVar(&sourceNodeURI, "source-node-uri", "",
+		"Base URI of a source chain node serving the Warp API, derived from --source-rpc by default")
+	relayCmd.Flags().StringVar(&signingSubnetID, "signing-subnet-id", "",
+		"Subnet whose validators sign the message, required when the source chain is the C-Chain")
+	relayCmd.Flags().Uint64Var(&quorumNumerator, "quorum-numerator", warp.WarpDefaultQuorumNumerator,
+		"Quorum numerator of the aggregate signature")
+	relayCmd.Flags().StringVar(&relayRewardAddress, "relayer-reward-address", "",
+		"Address to credit the relayer rewards to, the signer's address by default")
+	relayCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the signed transactions without broadcasting them")
+}
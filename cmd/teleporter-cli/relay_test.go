@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "no args",
+			args: []string{"relay"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "help",
+			args: []string{"relay", "--help"},
+			err:  nil,
+			out:  "Given the hash of a transaction on the source chain, this command extracts the",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestNodeURIFromRPC(t *testing.T) {
+	var tests = []struct {
+		rpcURL  string
+		nodeURI string
+	}{
+		{
+			rpcURL:  "http://127.0.0.1:9650/ext/bc/C/rpc",
+			nodeURI: "http://127.0.0.1:9650",
+		},
+		{
+			rpcURL:  "https://api.avax-test.network/ext/bc/2Z36RnQuk1hvsnFeGWzfZUfXNr7w1SjzmDQ78YxfTVNAkDq3nZ/rpc",
+			nodeURI: "https://api.avax-test.network",
+		},
+		{
+			rpcURL:  "http://127.0.0.1:9650",
+			nodeURI: "http://127.0.0.1:9650",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rpcURL, func(t *testing.T) {
+			require.Equal(t, tt.nodeURI, nodeURIFromRPC(tt.rpcURL))
+		})
+	}
+}
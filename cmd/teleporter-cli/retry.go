@@ -0,0 +1,220 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	retryGasLimit  uint64
+	retryFromBlock uint64
+)
+
+type retryOutput struct {
+	MessageID       string                   `json:"messageID" yaml:"messageID"`
+	MessageHash     string                   `json:"messageHash" yaml:"messageHash"`
+	TransactionHash string                   `json:"transactionHash" yaml:"transactionHash"`
+	Message         *teleporterMessageOutput `json:"message" yaml:"message"`
+}
+
+var retryExecutionCmd = &cobra.Command{
+	Use:   "retry-execution --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS [--gas-limit GAS_LIMIT] MESSAGE_ID",
+	Short: "Retries the execution of a message that failed to execute on the destination chain",
+	Long: `Given the ID of a message that was received but failed to execute, this command
+looks up the message in the MessageExecutionFailed event emitted on the destination
+chain, verifies that its hash matches the failed message hash stored by the
+TeleporterMessenger contract, and submits a retryMessageExecution transaction.
+The --rpc flag must point to the destination chain. The gas limit of the retry
+transaction is estimated unless --gas-limit is provided, and must leave enough gas
+for the message's required gas limit.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		messageID, err := parseID(args[0])
+		cobra.CheckErr(err)
+
+		ctx := context.Background()
+		teleporter, err := teleportermessenger.NewTeleporterMessenger(teleporterAddress, client)
+		cobra.CheckErr(err)
+		event, err := findFailedMessage(ctx, teleporter, messageID, retryFromBlock)
+		cobra.CheckErr(err)
+
+		expectedHash, err := teleporter.ReceivedFailedMessageHashes(&bind.CallOpts{Context: ctx}, messageID)
+		cobra.CheckErr(err)
+		messageHash, err := verifyMessageHash(event.Message, expectedHash)
+		cobra.CheckErr(err)
+
+		opts, err := newRetryTransactOpts(ctx)
+		cobra.CheckErr(err)
+		logger.Info("Retrying message execution",
+			zap.String("messageID", messageID.String()),
+			zap.String("sourceBlockchainID", ids.ID(event.SourceBlockchainID).String()))
+		tx, err := teleporter.RetryMessageExecution(opts, event.SourceBlockchainID, event.Message)
+		cobra.CheckErr(err)
+		receipt, err := waitForSuccess(ctx, client, tx)
+		cobra.CheckErr(err)
+
+		cobra.CheckErr(printRetryOutput(cmd, "Retry execution", messageID, messageHash, receipt.TxHash, event.Message))
+	},
+}
+
+var retrySendCmd = &cobra.Command{
+	Use:   "retry-send --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS [--gas-limit GAS_LIMIT] MESSAGE_ID",
+	Short: "Resubmits a previously sent message to the Warp precompile",
+	Long: `Given the ID of a message sent from the source chain, this command looks up the
+message in the SendCrossChainMessage event emitted on the source chain, verifies
+that its hash matches the hash returned by getMessageHash, and submits a
+retrySendCrossChainMessage transaction so that the message can be relayed again.
+The --rpc flag must point to the source chain. The gas limit of the retry
+transaction is estimated unless --gas-limit is provided.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		messageID, err := parseID(args[0])
+		cobra.CheckErr(err)
+
+		ctx := context.Background()
+		teleporter, err := teleportermessenger.NewTeleporterMessenger(teleporterAddress, client)
+		cobra.CheckErr(err)
+		event, err := findSentMessage(ctx, teleporter, messageID, retryFromBlock)
+		cobra.CheckErr(err)
+
+		expectedHash, err := teleporter.GetMessageHash(&bind.CallOpts{Context: ctx}, messageID)
+		cobra.CheckErr(err)
+		messageHash, err := verifyMessageHash(event.Message, expectedHash)
+		cobra.CheckErr(err)
+
+		opts, err := newRetryTransactOpts(ctx)
+		cobra.CheckErr(err)
+		logger.Info("Retrying message send",
+			zap.String("messageID", messageID.String()),
+			zap.String("destinationBlockchainID", ids.ID(event.DestinationBlockchainID).String()))
+		tx, err := teleporter.RetrySendCrossChainMessage(opts, event.Message)
+		cobra.CheckErr(err)
+		receipt, err := waitForSuccess(ctx, client, tx)
+		cobra.CheckErr(err)
+
+		cobra.CheckErr(printRetryOutput(cmd, "Retry send", messageID, messageHash, receipt.TxHash, event.Message))
+	},
+}
+
+// findFailedMessage returns the MessageExecutionFailed event emitted for the message
+func findFailedMessage(
+	ctx context.Context,
+	teleporter *teleportermessenger.TeleporterMessenger,
+	messageID ids.ID,
+	fromBlock uint64,
+) (*teleportermessenger.TeleporterMessengerMessageExecutionFailed, error) {
+	it, err := teleporter.FilterMessageExecutionFailed(
+		&bind.FilterOpts{Start: fromBlock, Context: ctx}, [][32]byte{messageID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	if !it.Next() {
+		if it.Error() != nil {
+			return nil, it.Error()
+		}
+		return nil, fmt.Errorf("no MessageExecutionFailed event found for message %s", messageID)
+	}
+	return it.Event, nil
+}
+
+// findSentMessage returns the SendCrossChainMessage event emitted for the message
+func findSentMessage(
+	ctx context.Context,
+	teleporter *teleportermessenger.TeleporterMessenger,
+	messageID ids.ID,
+	fromBlock uint64,
+) (*teleportermessenger.TeleporterMessengerSendCrossChainMessage, error) {
+	it, err := teleporter.FilterSendCrossChainMessage(
+		&bind.FilterOpts{Start: fromBlock, Context: ctx}, [][32]byte{messageID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	if !it.Next() {
+		if it.Error() != nil {
+			return nil, it.Error()
+		}
+		return nil, fmt.Errorf("no SendCrossChainMessage event found for message %s", messageID)
+	}
+	return it.Event, nil
+}
+
+// verifyMessageHash checks that the hash of the ABI encoded message matches the hash stored by the
+// TeleporterMessenger contract, which the contract requires before retrying the message.
+func verifyMessageHash(message teleportermessenger.TeleporterMessage, expectedHash [32]byte) (common.Hash, error) {
+	if expectedHash == [32]byte{} {
+		return common.Hash{}, fmt.Errorf("message hash not found, the message may already have been retried")
+	}
+	messageHash, err := teleportermessenger.HashTeleporterMessage(message)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if messageHash != expectedHash {
+		return common.Hash{}, fmt.Errorf("message hash %s does not match the stored hash %s",
+			messageHash.Hex(), common.Hash(expectedHash).Hex())
+	}
+	return messageHash, nil
+}
+
+func newRetryTransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := newTransactOpts(ctx, &signerKeyFlags, chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.GasLimit = retryGasLimit
+	return opts, nil
+}
+
+func printRetryOutput(
+	cmd *cobra.Command,
+	name string,
+	messageID ids.ID,
+	messageHash common.Hash,
+	txHash common.Hash,
+	message teleportermessenger.TeleporterMessage,
+) error {
+	if !isTableOutput() {
+		return printOutput(cmd, retryOutput{
+			MessageID:       messageID.String(),
+			MessageHash:     messageHash.Hex(),
+			TransactionHash: txHash.Hex(),
+			Message:         newTeleporterMessageOutput(message),
+		})
+	}
+	logger.Info("Retried Teleporter message",
+		zap.String("messageID", messageID.String()),
+		zap.String("messageHash", messageHash.Hex()),
+		zap.String("txHash", txHash.Hex()))
+	cmd.Println(name + " command ran successfully")
+	return nil
+}
+
+func addRetryFlags(cmd *cobra.Command) {
+	addRPCFlags(cmd)
+	addKeyFlags(cmd, &signerKeyFlags)
+	cmd.Flags().Uint64Var(&retryGasLimit, "gas-limit", 0,
+		"Gas limit of the retry transaction, estimated if not provided")
+	cmd.Flags().Uint64Var(&retryFromBlock, "from-block", 0, "Block to start searching for the message's event from")
+}
+
+func init() {
+	rootCmd.AddCommand(retryExecutionCmd)
+	rootCmd.AddCommand(retrySendCmd)
+	addRetryFlags(retryExecutionCmd)
+	addRetryFlags(retrySendCmd)
+}
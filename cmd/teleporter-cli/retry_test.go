@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryCmds(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "retry execution no args",
+			args: []string{"retry-execution"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "retry execution help",
+			args: []string{"retry-execution", "--help"},
+			err:  nil,
+			out:  "Given the ID of a message that was received but failed to execute",
+		},
+		{
+			name: "retry send no args",
+			args: []string{"retry-send"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "retry send help",
+			args: []string{"retry-send", "--help"},
+			err:  nil,
+			out:  "Given the ID of a message sent from the source chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestVerifyMessageHash(t *testing.T) {
+	message := createTestTeleporterMessage()
+	messageBytes, err := teleportermessenger.PackTeleporterMessage(message)
+	require.NoError(t, err)
+	expectedHash := crypto.Keccak256Hash(messageBytes)
+
+	messageHash, err := verifyMessageHash(message, expectedHash)
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, messageHash)
+
+	_, err = verifyMessageHash(message, [32]byte{})
+	require.ErrorContains(t, err, "message hash not found")
+
+	modified := createTestTeleporterMessage()
+	modified.Message = []byte{1, 2, 3}
+	_, err = verifyMessageHash(modified, expectedHash)
+	require.ErrorContains(t, err, "does not match the stored hash")
+}
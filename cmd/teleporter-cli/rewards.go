@@ -0,0 +1,284 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	rewardsRelayer     string
+	rewardsRelayers    []string
+	rewardsTokens      []string
+	rewardsRedeemToken string
+	rewardsFromBlock   uint64
+	rewardsToBlock     uint64
+)
+
+type rewardOutput struct {
+	Relayer  string `json:"relayer" yaml:"relayer"`
+	Token    string `json:"token" yaml:"token"`
+	Amount   string `json:"amount" yaml:"amount"`
+	Receipts int    `json:"receipts,omitempty" yaml:"receipts,omitempty"`
+}
+
+type redeemOutput struct {
+	TransactionHash string       `json:"transactionHash" yaml:"transactionHash"`
+	Event           *eventOutput `json:"event" yaml:"event"`
+}
+
+// rewardKey identifies the rewards earned by a relayer in a single fee token
+type rewardKey struct {
+	relayer common.Address
+	token   common.Address
+}
+
+type rewardTotal struct {
+	amount   *big.Int
+	receipts int
+}
+
+var rewardsCmd = &cobra.Command{
+	Use:   "rewards",
+	Short: "Checks, scans and redeems relayer rewards",
+	Long: `Checks, scans and redeems the rewards earned by relayers for delivering Teleporter
+messages. Relayer rewards are credited on the source chain of a message, once the
+receipt of the message is returned from the destination chain.`,
+	Args: cobra.NoArgs,
+}
+
+var rewardsCheckCmd = &cobra.Command{
+	Use:   "check --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS --relayer ADDRESS --token ADDRESS",
+	Short: "Checks the redeemable reward amount of a relayer",
+	Long: `Checks the amount of rewards redeemable by the relayer in each of the given fee
+tokens, using checkRelayerRewardAmount.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		relayer, err := parseAddress(rewardsRelayer)
+		cobra.CheckErr(err)
+		tokens, err := parseAddresses(rewardsTokens)
+		cobra.CheckErr(err)
+
+		teleporter, err := teleportermessenger.NewTeleporterMessengerCaller(teleporterAddress, client)
+		cobra.CheckErr(err)
+		rewardOuts := []rewardOutput{}
+		for _, token := range tokens {
+			amount, err := teleporter.CheckRelayerRewardAmount(
+				&bind.CallOpts{Context: context.Background()}, relayer, token)
+			cobra.CheckErr(err)
+			rewardOuts = append(rewardOuts, rewardOutput{
+				Relayer: relayer.Hex(),
+				Token:   token.Hex(),
+				Amount:  amount.String(),
+			})
+		}
+
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, rewardOuts))
+			return
+		}
+		for _, rewardOut := range rewardOuts {
+			logger.Info("Redeemable relayer reward",
+				zap.String("relayer", rewardOut.Relayer),
+				zap.String("token", rewardOut.Token),
+				zap.String("amount", rewardOut.Amount))
+		}
+		cmd.Println("Rewards check command ran successfully")
+	},
+}
+
+var rewardsScanCmd = &cobra.Command{
+	Use:   "scan --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS [--relayer ADDRESS] [--from-block BLOCK]",
+	Short: "Computes the rewards earned by relayers over a block range",
+	Long: `Walks the ReceiptReceived events emitted over a block range and computes the total
+rewards earned per relayer and fee token, along with the number of receipts the
+rewards were earned from. The totals include rewards that have already been
+redeemed. Use --relayer to only include the given relayer reward addresses.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		relayers, err := parseAddresses(rewardsRelayers)
+		cobra.CheckErr(err)
+
+		teleporter, err := teleportermessenger.NewTeleporterMessengerFilterer(teleporterAddress, client)
+		cobra.CheckErr(err)
+		filterOpts := &bind.FilterOpts{Start: rewardsFromBlock, Context: context.Background()}
+		if rewardsToBlock != 0 {
+			filterOpts.End = &rewardsToBlock
+		}
+		it, err := teleporter.FilterReceiptReceived(filterOpts, nil, nil, relayers)
+		cobra.CheckErr(err)
+		defer it.Close()
+		var events []*teleportermessenger.TeleporterMessengerReceiptReceived
+		for it.Next() {
+			events = append(events, it.Event)
+		}
+		cobra.CheckErr(it.Error())
+
+		rewardOuts := sumRewards(events)
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, rewardOuts))
+			return
+		}
+		for _, rewardOut := range rewardOuts {
+			logger.Info("Earned relayer reward",
+				zap.String("relayer", rewardOut.Relayer),
+				zap.String("token", rewardOut.Token),
+				zap.String("amount", rewardOut.Amount),
+				zap.Int("receipts", rewardOut.Receipts))
+		}
+		cmd.Printf("Scanned %d receipts\n", len(events))
+		cmd.Println("Rewards scan command ran successfully")
+	},
+}
+
+var rewardsRedeemCmd = &cobra.Command{
+	Use:   "redeem --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS --token ADDRESS",
+	Short: "Redeems the rewards of the signer in a fee token",
+	Long: `Redeems the rewards earned by the signer in the given fee token by submitting a
+redeemRelayerRewards transaction, and verifies that the RelayerRewardsRedeemed
+event emitted matches the amount that was redeemable before the transaction.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		token, err := parseAddress(rewardsRedeemToken)
+		cobra.CheckErr(err)
+
+		ctx := context.Background()
+		chainID, err := client.ChainID(ctx)
+		cobra.CheckErr(err)
+		opts, err := newTransactOpts(ctx, &signerKeyFlags, chainID)
+		cobra.CheckErr(err)
+
+		event, err := redeemRelayerRewards(ctx, client, opts, teleporterAddress, token)
+		cobra.CheckErr(err)
+
+		if !isTableOutput() {
+			eventOut, err := newEventOutput(event, &event.Raw)
+			cobra.CheckErr(err)
+			cobra.CheckErr(printOutput(cmd, redeemOutput{
+				TransactionHash: event.Raw.TxHash.Hex(),
+				Event:           eventOut,
+			}))
+			return
+		}
+		logger.Info("Redeemed relayer rewards",
+			zap.String("txHash", event.Raw.TxHash.Hex()),
+			zap.String("redeemer", event.Redeemer.Hex()),
+			zap.String("token", event.Asset.Hex()),
+			zap.String("amount", event.Amount.String()))
+		cmd.Println("Rewards redeem command ran successfully")
+	},
+}
+
+// redeemRelayerRewards redeems the rewards of the sender in the fee token, and verifies the
+// RelayerRewardsRedeemed event against the redeemable amount before the transaction.
+func redeemRelayerRewards(
+	ctx context.Context,
+	backend contractBackend,
+	opts *bind.TransactOpts,
+	teleporterAddress common.Address,
+	token common.Address,
+) (*teleportermessenger.TeleporterMessengerRelayerRewardsRedeemed, error) {
+	teleporter, err := teleportermessenger.NewTeleporterMessenger(teleporterAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	expectedAmount, err := teleporter.CheckRelayerRewardAmount(&bind.CallOpts{Context: ctx}, opts.From, token)
+	if err != nil {
+		return nil, err
+	}
+	if expectedAmount.Sign() == 0 {
+		return nil, fmt.Errorf("no rewards to redeem for %s in token %s", opts.From.Hex(), token.Hex())
+	}
+
+	tx, err := teleporter.RedeemRelayerRewards(opts, token)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := waitForSuccess(ctx, backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	event, err := findEvent(receipt, teleporterAddress, teleporter.ParseRelayerRewardsRedeemed)
+	if err != nil {
+		return nil, err
+	}
+	if event.Redeemer != opts.From || event.Asset != token || event.Amount.Cmp(expectedAmount) != 0 {
+		return nil, fmt.Errorf("unexpected RelayerRewardsRedeemed event: redeemer %s, asset %s, amount %s, "+
+			"expected amount %s", event.Redeemer.Hex(), event.Asset.Hex(), event.Amount, expectedAmount)
+	}
+	return event, nil
+}
+
+// sumRewards totals the fees of the receipts per relayer reward address and fee token.
+// Receipts for messages without a fee do not earn rewards, and are skipped.
+func sumRewards(events []*teleportermessenger.TeleporterMessengerReceiptReceived) []rewardOutput {
+	totals := make(map[rewardKey]*rewardTotal)
+	for _, event := range events {
+		if event.FeeInfo.Amount == nil || event.FeeInfo.Amount.Sign() == 0 {
+			continue
+		}
+		key := rewardKey{
+			relayer: event.RelayerRewardAddress,
+			token:   event.FeeInfo.FeeTokenAddress,
+		}
+		total, ok := totals[key]
+		if !ok {
+			total = &rewardTotal{amount: big.NewInt(0)}
+			totals[key] = total
+		}
+		total.amount.Add(total.amount, event.FeeInfo.Amount)
+		total.receipts++
+	}
+
+	rewardOuts := []rewardOutput{}
+	for key, total := range totals {
+		rewardOuts = append(rewardOuts, rewardOutput{
+			Relayer:  key.relayer.Hex(),
+			Token:    key.token.Hex(),
+			Amount:   total.amount.String(),
+			Receipts: total.receipts,
+		})
+	}
+	sort.Slice(rewardOuts, func(i, j int) bool {
+		if rewardOuts[i].Relayer != rewardOuts[j].Relayer {
+			return rewardOuts[i].Relayer < rewardOuts[j].Relayer
+		}
+		return rewardOuts[i].Token < rewardOuts[j].Token
+	})
+	return rewardOuts
+}
+
+func init() {
+	rootCmd.AddCommand(rewardsCmd)
+	rewardsCmd.AddCommand(rewardsCheckCmd, rewardsScanCmd, rewardsRedeemCmd)
+
+	addRPCFlags(rewardsCheckCmd)
+	rewardsCheckCmd.Flags().StringVar(&rewardsRelayer, "relayer", "", "Relayer reward address to check")
+	rewardsCheckCmd.Flags().StringSliceVar(&rewardsTokens, "token", nil, "Fee token addresses to check")
+	err := rewardsCheckCmd.MarkFlagRequired("relayer")
+	cobra.CheckErr(err)
+	err = rewardsCheckCmd.MarkFlagRequired("token")
+	cobra.CheckErr(err)
+
+	addRPCFlags(rewardsScanCmd)
+	rewardsScanCmd.Flags().StringSliceVar(&rewardsRelayers, "relayer", nil,
+		"Relayer reward addresses to include, all relayers by default")
+	rewardsScanCmd.Flags().Uint64Var(&rewardsFromBlock, "from-block", 0, "Block to start scanning from")
+	rewardsScanCmd.Flags().Uint64Var(&rewardsToBlock, "to-block", 0, "Block to stop scanning at, latest by default")
+
+	addRPCFlags(rewardsRedeemCmd)
+	addKeyFlags(rewardsRedeemCmd, &signerKeyFlags)
+	rewardsRedeemCmd.Flags().StringVar(&rewardsRedeemToken, "token", "", "Fee token address to redeem rewards in")
+	err = rewardsRedeemCmd.MarkFlagRequired("token")
+	cobra.CheckErr(err)
+}
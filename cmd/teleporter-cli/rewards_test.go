@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardsCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "help",
+			args: []string{"rewards", "--help"},
+			err:  nil,
+			out:  "Checks, scans and redeems the rewards earned by relayers",
+		},
+		{
+			name: "scan args",
+			args: []string{"rewards", "scan", "arg"},
+			err:  fmt.Errorf("unknown command \"arg\" for \"teleporter-cli rewards scan\""),
+		},
+		{
+			name: "redeem help",
+			args: []string{"rewards", "redeem", "--help"},
+			err:  nil,
+			out:  "Redeems the rewards earned by the signer in the given fee token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestRewardsCheckCmd(t *testing.T) {
+	relayer := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	token := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	url := newTestRPCServer(t, map[string]func(args []interface{}) []interface{}{
+		"checkRelayerRewardAmount": func(args []interface{}) []interface{} {
+			assert.Equal(t, []interface{}{relayer, token}, args)
+			return []interface{}{big.NewInt(42)}
+		},
+	})
+	args := []string{"rewards", "check", "--rpc", url, "--teleporter-address", common.Address{}.Hex(),
+		"--relayer", relayer.Hex(), "--token", token.Hex()}
+
+	var tests = []struct {
+		name   string
+		output string
+		err    error
+		out    string
+	}{
+		{
+			name:   "table",
+			output: tableOutput,
+			out:    "Rewards check command ran successfully",
+		},
+		{
+			name:   "json",
+			output: jsonOutput,
+			out:    `"amount": "42"`,
+		},
+		{
+			name:   "invalid output",
+			output: "xml",
+			err:    fmt.Errorf("invalid output format xml"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// The root command's persistent pre-run function sets the logger
+			logger = nil
+			t.Cleanup(func() {
+				logger = logging.NoLog{}
+				outputFormat = tableOutput
+			})
+			out, err := executeTestCmd(t, rootCmd, append(args, "--output", tt.output)...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestSumRewards(t *testing.T) {
+	relayerA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	relayerB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	token1 := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	token2 := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	receipt := func(relayer, token common.Address, amount int64) *teleportermessenger.TeleporterMessengerReceiptReceived {
+		return &teleportermessenger.TeleporterMessengerReceiptReceived{
+			RelayerRewardAddress: relayer,
+			FeeInfo: teleportermessenger.TeleporterFeeInfo{
+				FeeTokenAddress: token,
+				Amount:          big.NewInt(amount),
+			},
+		}
+	}
+
+	rewards := sumRewards([]*teleportermessenger.TeleporterMessengerReceiptReceived{
+		receipt(relayerB, token1, 5),
+		receipt(relayerA, token2, 3),
+		receipt(relayerA, token1, 1),
+		receipt(relayerA, token1, 2),
+		receipt(relayerB, common.Address{}, 0),
+	})
+	require.Equal(t, []rewardOutput{
+		{Relayer: relayerA.Hex(), Token: token1.Hex(), Amount: "3", Receipts: 2},
+		{Relayer: relayerA.Hex(), Token: token2.Hex(), Amount: "3", Receipts: 1},
+		{Relayer: relayerB.Hex(), Token: token1.Hex(), Amount: "5", Receipts: 1},
+	}, rewards)
+	require.Empty(t, sumRewards(nil))
+}
@@ -37,6 +37,8 @@ func Execute() {
 func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	logLevelArg := rootCmd.PersistentFlags().StringP("log", "l", "", "Log level i.e. debug, info...")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", tableOutput,
+		"Output format i.e. table, json, yaml")
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		return rootPreRunE(logLevelArg)
 	}
@@ -51,11 +53,19 @@ func rootPreRunE(logLevelArg *string) error {
 	if err != nil {
 		return err
 	}
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+	// Keep stdout reserved for the machine readable output if one is selected
+	logOutput := os.Stdout
+	if !isTableOutput() {
+		logOutput = os.Stderr
+	}
 	logger = logging.NewLogger(
 		"teleporter-cli",
 		logging.NewWrappedCore(
 			logLevel,
-			os.Stdout,
+			logOutput,
 			logging.Plain.ConsoleEncoder(),
 		),
 	)
@@ -67,8 +77,11 @@ func rootPreRunE(logLevelArg *string) error {
 	return nil
 }
 
+// callPersistentPreRunE calls the persistent pre-run function of the closest ancestor of the command that has
+// one. Cobra only runs the closest persistent pre-run function, so commands that define their own must call it
+// for the root command's to run, including when they are nested in a group command.
 func callPersistentPreRunE(cmd *cobra.Command, args []string) error {
-	if parent := cmd.Parent(); parent != nil {
+	for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
 		if parent.PersistentPreRunE != nil {
 			return parent.PersistentPreRunE(parent, args)
 		}
@@ -2,24 +2,116 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/utils/logging"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
 
+// TestMain sets the globals that the root command's persistent pre-run function sets, for the tests that call
+// the command functions directly instead of executing the root command.
+func TestMain(m *testing.M) {
+	logger = logging.NoLog{}
+	abi, err := teleportermessenger.TeleporterMessengerMetaData.GetAbi()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	teleporterABI = abi
+	os.Exit(m.Run())
+}
+
 func executeTestCmd(t *testing.T, c *cobra.Command, args ...string) (string, error) {
 	buf := new(bytes.Buffer)
 	c.SetOut(buf)
 	c.SetErr(buf)
 	c.SetArgs(args)
+	resetHelpFlags(c)
 
 	err := c.Execute()
 	return strings.TrimSpace(buf.String()), err
 }
 
+// newTestRPCServer starts a JSON-RPC server that answers eth_call requests to the TeleporterMessenger
+// contract with the values returned by the function of the called method, and returns its URL.
+func newTestRPCServer(t *testing.T, calls map[string]func(args []interface{}) []interface{}) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0"}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp["error"] = map[string]interface{}{"code": -32700, "message": err.Error()}
+		} else if result, err := testRPCCall(req.Method, req.Params, calls); err != nil {
+			resp["id"] = req.ID
+			resp["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+		} else {
+			resp["id"] = req.ID
+			resp["result"] = hexutil.Bytes(result)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func testRPCCall(
+	method string,
+	params []json.RawMessage,
+	calls map[string]func(args []interface{}) []interface{},
+) ([]byte, error) {
+	if method != "eth_call" || len(params) == 0 {
+		return nil, fmt.Errorf("unexpected request %s", method)
+	}
+	var callArgs struct {
+		Input hexutil.Bytes `json:"input"`
+		Data  hexutil.Bytes `json:"data"`
+	}
+	if err := json.Unmarshal(params[0], &callArgs); err != nil {
+		return nil, err
+	}
+	input := callArgs.Input
+	if len(input) == 0 {
+		input = callArgs.Data
+	}
+	abiMethod, err := teleporterABI.MethodById(input)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := calls[abiMethod.Name]
+	if !ok {
+		return nil, fmt.Errorf("unexpected call to %s", abiMethod.Name)
+	}
+	args, err := abiMethod.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	return abiMethod.Outputs.Pack(call(args)...)
+}
+
+// resetHelpFlags clears help flags set by previous executions, since cobra does
+// not reset flag values between executions of the same command tree.
+func resetHelpFlags(c *cobra.Command) {
+	if f := c.Flags().Lookup("help"); f != nil {
+		_ = f.Value.Set("false")
+	}
+	for _, sub := range c.Commands() {
+		resetHelpFlags(sub)
+	}
+}
+
 func TestRootCmd(t *testing.T) {
 	var tests = []struct {
 		name string
@@ -0,0 +1,137 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var sendMessageFlags messageFlags
+
+type sendOutput struct {
+	TransactionHash string       `json:"transactionHash" yaml:"transactionHash"`
+	MessageID       string       `json:"messageID" yaml:"messageID"`
+	Event           *eventOutput `json:"event" yaml:"event"`
+}
+
+var sendCmd = &cobra.Command{
+	Use: "send --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS " +
+		"--destination-blockchain-id BLOCKCHAIN_ID --destination-address ADDRESS [--file FILE]",
+	Short: "Signs and submits a sendCrossChainMessage transaction",
+	Long: `Sends a Teleporter message by signing and submitting a transaction calling
+sendCrossChainMessage on the TeleporterMessenger contract. The message is described
+by flags or a JSON file, as in the encode send command, and is checked against the
+invariants of the contract before it is sent. If a fee is provided, the
+TeleporterMessenger contract is first approved to spend the fee token if needed.
+The key used to sign the transactions is read from an environment variable or an
+encrypted keystore file. The ID of the sent message is parsed from the
+SendCrossChainMessage event and printed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, err := sendMessageFlags.teleporterMessageInput()
+		cobra.CheckErr(err)
+		// Check the invariants of the contract up front, rather than failing with a revert
+		analysis, err := teleportermessenger.ValidateMessageInput(input)
+		cobra.CheckErr(err)
+		logger.Debug("Validated message",
+			zap.Int("maxSize", analysis.Size),
+			zap.Uint64("maxCalldataGas", analysis.CalldataGas))
+
+		ctx := context.Background()
+		chainID, err := client.ChainID(ctx)
+		cobra.CheckErr(err)
+		opts, err := newTransactOpts(ctx, &signerKeyFlags, chainID)
+		cobra.CheckErr(err)
+
+		receipt, event, err := sendCrossChainMessage(ctx, client, opts, teleporterAddress, input)
+		cobra.CheckErr(err)
+
+		if !isTableOutput() {
+			eventOut, err := newEventOutput(event, &event.Raw)
+			cobra.CheckErr(err)
+			cobra.CheckErr(printOutput(cmd, sendOutput{
+				TransactionHash: receipt.TxHash.Hex(),
+				MessageID:       ids.ID(event.MessageID).String(),
+				Event:           eventOut,
+			}))
+			return
+		}
+		logger.Info("Sent Teleporter message",
+			zap.String("txHash", receipt.TxHash.Hex()),
+			zap.Any("event", event))
+		cmd.Printf("Message ID: %s (%s)\n", ids.ID(event.MessageID), common.Hash(event.MessageID).Hex())
+		cmd.Println("Send command ran successfully")
+	},
+}
+
+// sendCrossChainMessage approves the fee amount if necessary, sends the message, and returns the
+// receipt of the transaction along with the SendCrossChainMessage event it emitted.
+func sendCrossChainMessage(
+	ctx context.Context,
+	backend contractBackend,
+	opts *bind.TransactOpts,
+	teleporterAddress common.Address,
+	input teleportermessenger.TeleporterMessageInput,
+) (*types.Receipt, *teleportermessenger.TeleporterMessengerSendCrossChainMessage, error) {
+	if input.FeeInfo.Amount.Sign() > 0 {
+		err := ensureERC20Allowance(
+			ctx, backend, opts, input.FeeInfo.FeeTokenAddress, teleporterAddress, input.FeeInfo.Amount)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	teleporter, err := teleportermessenger.NewTeleporterMessenger(teleporterAddress, backend)
+	if err != nil {
+		return nil, nil, err
+	}
+	tx, err := teleporter.SendCrossChainMessage(opts, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, err := waitForSuccess(ctx, backend, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event, err := findEvent(receipt, teleporterAddress, teleporter.ParseSendCrossChainMessage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return receipt, event, nil
+}
+
+// findEvent returns the first log emitted by the contract in the receipt that is successfully parsed by parser
+func findEvent[T any](
+	receipt *types.Receipt,
+	contractAddress common.Address,
+	parser func(log types.Log) (T, error),
+) (T, error) {
+	for _, log := range receipt.Logs {
+		if log.Address != contractAddress {
+			continue
+		}
+		event, err := parser(*log)
+		if err == nil {
+			return event, nil
+		}
+	}
+	return *new(T), fmt.Errorf("failed to find %T event in transaction %s", *new(T), receipt.TxHash.Hex())
+}
+
+func init() {
+	rootCmd.AddCommand(sendCmd)
+	addRPCFlags(sendCmd)
+	addMessageInputFlags(sendCmd, &sendMessageFlags)
+	addKeyFlags(sendCmd, &signerKeyFlags)
+}
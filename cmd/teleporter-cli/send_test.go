@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	exampleerc20 "github.com/ava-labs/teleporter/abi-bindings/go/Mocks/ExampleERC20"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	teleporterUtils "github.com/ava-labs/teleporter/utils/teleporter-utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "args",
+			args: []string{"send", "arg"},
+			err:  fmt.Errorf("unknown command \"arg\" for \"teleporter-cli send\""),
+		},
+		{
+			name: "help",
+			args: []string{"send", "--help"},
+			err:  nil,
+			out:  "Sends a Teleporter message by signing and submitting a transaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestSendCrossChainMessage(t *testing.T) {
+	ctx := context.Background()
+	sourceBlockchainID := ids.GenerateTestID()
+	backend, key := newTestWarpBackend(t, sourceBlockchainID)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, simulatedChainID)
+	require.NoError(t, err)
+	opts.Context = ctx
+
+	teleporterAddress, tx, _, err := teleportermessenger.DeployTeleporterMessenger(opts, backend)
+	require.NoError(t, err)
+	_, err = waitForSuccess(ctx, backend, tx)
+	require.NoError(t, err)
+	tokenAddress, tx, token, err := exampleerc20.DeployExampleERC20(opts, backend)
+	require.NoError(t, err)
+	_, err = waitForSuccess(ctx, backend, tx)
+	require.NoError(t, err)
+
+	destinationBlockchainID := ids.GenerateTestID()
+	fee := big.NewInt(100)
+	input := teleportermessenger.TeleporterMessageInput{
+		DestinationBlockchainID: destinationBlockchainID,
+		DestinationAddress:      common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"),
+		FeeInfo: teleportermessenger.TeleporterFeeInfo{
+			FeeTokenAddress: tokenAddress,
+			Amount:          fee,
+		},
+		RequiredGasLimit:        big.NewInt(100_000),
+		AllowedRelayerAddresses: []common.Address{},
+		Message:                 []byte{1, 2, 3, 4},
+	}
+
+	for nonce := int64(1); nonce <= 2; nonce++ {
+		receipt, event, err := sendCrossChainMessage(ctx, backend, opts, teleporterAddress, input)
+		require.NoError(t, err)
+		require.Equal(t, receipt.TxHash, event.Raw.TxHash)
+
+		messageID, err := teleporterUtils.CalculateMessageID(
+			teleporterAddress, sourceBlockchainID, destinationBlockchainID, big.NewInt(nonce))
+		require.NoError(t, err)
+		require.Equal(t, messageID, ids.ID(event.MessageID))
+		require.Equal(t, opts.From, event.Message.OriginSenderAddress)
+		require.Equal(t, input.Message, event.Message.Message)
+		require.Equal(t, fee, event.FeeInfo.Amount)
+
+		// The fee is approved for each message, and transferred to the TeleporterMessenger contract
+		allowance, err := token.Allowance(&bind.CallOpts{Context: ctx}, opts.From, teleporterAddress)
+		require.NoError(t, err)
+		require.Zero(t, allowance.Sign())
+		balance, err := token.BalanceOf(&bind.CallOpts{Context: ctx}, teleporterAddress)
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).Mul(fee, big.NewInt(nonce)), balance)
+	}
+}
@@ -0,0 +1,315 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/ethclient/subnetevmclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/rpc"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	simulateSourceRPC          string
+	simulateSourceTx           string
+	simulateSourceBlockchainID string
+)
+
+var (
+	receiveTeleporterMessageSelector = crypto.Keccak256([]byte("receiveTeleporterMessage(bytes32,address,bytes)"))[:4]
+	receiveTeleporterMessageArgs     = newABIArguments("bytes32", "address", "bytes")
+
+	// simulatedTeleporterBalance is the balance the Teleporter address is overridden with, so that the
+	// simulated call can not fail for lack of funds to pay for gas.
+	simulatedTeleporterBalance = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+type simulateOutput struct {
+	SourceBlockchainID string `json:"sourceBlockchainID" yaml:"sourceBlockchainID"`
+	MessageNonce       string `json:"messageNonce" yaml:"messageNonce"`
+	DestinationAddress string `json:"destinationAddress" yaml:"destinationAddress"`
+	RequiredGasLimit   string `json:"requiredGasLimit" yaml:"requiredGasLimit"`
+	// GasUsed is the gas used by the execution of the message, if it executes with any gas limit
+	GasUsed       string `json:"gasUsed,omitempty" yaml:"gasUsed,omitempty"`
+	SufficientGas bool   `json:"sufficientGas" yaml:"sufficientGas"`
+	Executes      bool   `json:"executes" yaml:"executes"`
+	RevertReason  string `json:"revertReason,omitempty" yaml:"revertReason,omitempty"`
+}
+
+var simulateCmd = &cobra.Command{
+	Use: "simulate --rpc DESTINATION_RPC_URL --teleporter-address CONTRACT_ADDRESS " +
+		"(--source-blockchain-id ID MESSAGE_BYTES | --source-rpc SOURCE_RPC_URL --source-tx TX_HASH)",
+	Short: "Simulates the execution of a Teleporter message on the destination chain",
+	Long: `Simulates the execution of Teleporter messages on the destination chain at --rpc,
+without relaying them. The messages are given either as hex encoded Teleporter
+message bytes sent from --source-blockchain-id, or as the messages sent by the
+--source-tx transaction on the chain at --source-rpc.
+
+For each message, receiveTeleporterMessage is called on the message's destination
+contract with eth_call from the Teleporter address, as the TeleporterMessenger
+contract would when delivering the message, with the message's requiredGasLimit.
+The command reports whether the message executes, the gas its execution uses
+compared to its requiredGasLimit, and the revert reason of failed executions.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		messages, err := simulatedMessages(ctx, args)
+		cobra.CheckErr(err)
+
+		simulator := newRPCExecutionSimulator(client)
+		outs := []*simulateOutput{}
+		for _, m := range messages {
+			out, err := simulateMessageExecution(ctx, simulator, teleporterAddress, m.sourceBlockchainID, m.message)
+			cobra.CheckErr(err)
+			outs = append(outs, out)
+		}
+
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, outs))
+			return
+		}
+		for _, out := range outs {
+			logger.Info("Simulated message execution",
+				zap.String("sourceBlockchainID", out.SourceBlockchainID),
+				zap.String("messageNonce", out.MessageNonce),
+				zap.String("destinationAddress", out.DestinationAddress),
+				zap.String("requiredGasLimit", out.RequiredGasLimit),
+				zap.String("gasUsed", out.GasUsed),
+				zap.Bool("sufficientGas", out.SufficientGas),
+				zap.Bool("executes", out.Executes),
+				zap.String("revertReason", out.RevertReason))
+		}
+		cmd.Println("Simulate command ran successfully")
+	},
+}
+
+type simulatedMessage struct {
+	sourceBlockchainID ids.ID
+	message            *teleportermessenger.TeleporterMessage
+}
+
+// simulatedMessages returns the message given as an argument, or the messages sent by the source transaction
+func simulatedMessages(ctx context.Context, args []string) ([]simulatedMessage, error) {
+	if (len(args) == 0) == (simulateSourceTx == "") {
+		return nil, fmt.Errorf("either MESSAGE_BYTES or --source-tx must be provided")
+	}
+	if len(args) == 1 {
+		sourceBlockchainID, err := parseID(simulateSourceBlockchainID)
+		if err != nil {
+			return nil, err
+		}
+		b, err := parseHexBytes(args[0])
+		if err != nil {
+			return nil, err
+		}
+		message, err := teleportermessenger.UnpackTeleporterMessage(b)
+		if err != nil {
+			return nil, err
+		}
+		return []simulatedMessage{{sourceBlockchainID: sourceBlockchainID, message: message}}, nil
+	}
+
+	if simulateSourceRPC == "" {
+		return nil, fmt.Errorf("--source-rpc must be provided with --source-tx")
+	}
+	sourceClient, err := ethclient.Dial(simulateSourceRPC)
+	if err != nil {
+		return nil, err
+	}
+	defer sourceClient.Close()
+	receipt, err := sourceClient.TransactionReceipt(ctx, common.HexToHash(simulateSourceTx))
+	if err != nil {
+		return nil, err
+	}
+	messages, err := receiptTeleporterMessages(receipt)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no Teleporter messages sent by transaction %s", simulateSourceTx)
+	}
+	return messages, nil
+}
+
+// receiptTeleporterMessages returns the Teleporter messages sent in Warp messages by the TeleporterMessenger
+// contract in the receipt. Warp messages sent by other contracts are skipped before their payload is unpacked,
+// since it is not a Teleporter message.
+func receiptTeleporterMessages(receipt *types.Receipt) ([]simulatedMessage, error) {
+	var messages []simulatedMessage
+	for _, log := range receipt.Logs {
+		if log.Address != warp.ContractAddress {
+			continue
+		}
+		unsignedMsg, addressedCall, err := parseAddressedCallWarpLog(*log)
+		if err != nil {
+			return nil, err
+		}
+		if common.BytesToAddress(addressedCall.SourceAddress) != teleporterAddress {
+			continue
+		}
+		message, err := teleportermessenger.UnpackTeleporterMessage(addressedCall.Payload)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, simulatedMessage{sourceBlockchainID: unsignedMsg.SourceChainID, message: message})
+	}
+	return messages, nil
+}
+
+// executionSimulator is the subset of the destination chain's RPC API used to simulate message execution
+type executionSimulator interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	EstimateGas(ctx context.Context, msg interfaces.CallMsg) (uint64, error)
+	CallContract(
+		ctx context.Context,
+		msg interfaces.CallMsg,
+		blockNumber *big.Int,
+		overrides *map[common.Address]subnetevmclient.OverrideAccount,
+	) ([]byte, error)
+}
+
+// rpcExecutionSimulator implements executionSimulator with an RPC client, using the subnet-evm client for
+// calls with state overrides.
+type rpcExecutionSimulator struct {
+	ethclient.Client
+	overrideClient *subnetevmclient.Client
+}
+
+func newRPCExecutionSimulator(c ethclient.Client) *rpcExecutionSimulator {
+	return &rpcExecutionSimulator{Client: c, overrideClient: subnetevmclient.New(c.Client())}
+}
+
+func (s *rpcExecutionSimulator) CallContract(
+	ctx context.Context,
+	msg interfaces.CallMsg,
+	blockNumber *big.Int,
+	overrides *map[common.Address]subnetevmclient.OverrideAccount,
+) ([]byte, error) {
+	return s.overrideClient.CallContract(ctx, msg, blockNumber, overrides)
+}
+
+// simulateMessageExecution simulates the call the TeleporterMessenger contract makes to the message's destination
+// contract when the message is delivered. The call is made from the Teleporter address, which is given enough
+// balance by a state override, with the message's requiredGasLimit available for its execution. If the call
+// fails, the gas the execution uses with any gas limit is estimated to tell insufficient gas from reverts.
+func simulateMessageExecution(
+	ctx context.Context,
+	simulator executionSimulator,
+	teleporterAddress common.Address,
+	sourceBlockchainID ids.ID,
+	message *teleportermessenger.TeleporterMessage,
+) (*simulateOutput, error) {
+	out := &simulateOutput{
+		SourceBlockchainID: sourceBlockchainID.String(),
+		MessageNonce:       bigIntOutput(message.MessageNonce),
+		DestinationAddress: message.DestinationAddress.Hex(),
+		RequiredGasLimit:   bigIntOutput(message.RequiredGasLimit),
+	}
+
+	code, err := simulator.CodeAt(ctx, message.DestinationAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		out.RevertReason = "destination address has no contract code, the message would be stored as failed"
+		return out, nil
+	}
+
+	args, err := receiveTeleporterMessageArgs.Pack(
+		[32]byte(sourceBlockchainID), message.OriginSenderAddress, message.Message)
+	if err != nil {
+		return nil, err
+	}
+	data := append(append([]byte{}, receiveTeleporterMessageSelector...), args...)
+	if !message.RequiredGasLimit.IsUint64() {
+		return nil, fmt.Errorf("required gas limit %s does not fit in a uint64", message.RequiredGasLimit)
+	}
+	callMsg := interfaces.CallMsg{
+		From: teleporterAddress,
+		To:   &message.DestinationAddress,
+		Gas:  message.RequiredGasLimit.Uint64() + intrinsicGas(data),
+		Data: data,
+	}
+	overrides := map[common.Address]subnetevmclient.OverrideAccount{
+		teleporterAddress: {Balance: simulatedTeleporterBalance},
+	}
+	_, callErr := simulator.CallContract(ctx, callMsg, nil, &overrides)
+	out.Executes = callErr == nil
+
+	estimateMsg := callMsg
+	estimateMsg.Gas = 0
+	gas, estimateErr := simulator.EstimateGas(ctx, estimateMsg)
+	if estimateErr == nil {
+		gasUsed := new(big.Int).SetUint64(gas - intrinsicGas(data))
+		out.GasUsed = gasUsed.String()
+		out.SufficientGas = gasUsed.Cmp(message.RequiredGasLimit) <= 0
+	}
+	switch {
+	case callErr != nil && estimateErr != nil:
+		// The execution reverts regardless of the gas limit, so the estimate's error holds the revert reason
+		out.RevertReason = revertReason(estimateErr)
+	case callErr != nil:
+		out.SufficientGas = false
+		out.RevertReason = revertReason(callErr)
+	default:
+		// The execution succeeds within the required gas limit even if the estimate differs
+		out.SufficientGas = true
+	}
+	return out, nil
+}
+
+// intrinsicGas returns the gas charged for a call with the calldata before its execution starts
+func intrinsicGas(data []byte) uint64 {
+	gas := params.TxGas
+	for _, b := range data {
+		if b == 0 {
+			gas += params.TxDataZeroGas
+		} else {
+			gas += params.TxDataNonZeroGasEIP2028
+		}
+	}
+	return gas
+}
+
+// revertReason returns the decoded Error(string) revert reason of a failed call, or the error message
+func revertReason(err error) string {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if b, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(b); unpackErr == nil {
+					return reason
+				}
+			}
+		}
+	}
+	return err.Error()
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+	addRPCFlags(simulateCmd)
+	simulateCmd.Flags().StringVar(&simulateSourceBlockchainID, "source-blockchain-id", "",
+		"Blockchain ID of the source chain of MESSAGE_BYTES")
+	simulateCmd.Flags().StringVar(&simulateSourceRPC, "source-rpc", "",
+		"RPC endpoint of the source chain of --source-tx")
+	simulateCmd.Flags().StringVar(&simulateSourceTx, "source-tx", "",
+		"Hash of the transaction that sent the messages to simulate")
+}
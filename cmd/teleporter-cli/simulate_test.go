@@ -0,0 +1,206 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient/subnetevmclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "too many args",
+			args: []string{"simulate", "0x01", "0x02"},
+			err:  fmt.Errorf("accepts at most 1 arg(s), received 2"),
+		},
+		{
+			name: "help",
+			args: []string{"simulate", "--help"},
+			out:  "Simulates the execution of Teleporter messages on the destination chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+// testDataError is an RPC error carrying the hex encoded revert data of a failed call
+type testDataError struct {
+	data string
+}
+
+func (e testDataError) Error() string          { return "execution reverted" }
+func (e testDataError) ErrorData() interface{} { return e.data }
+
+func newTestRevertError(t *testing.T, reason string) error {
+	errorType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	args, err := abi.Arguments{{Type: errorType}}.Pack(reason)
+	require.NoError(t, err)
+	// The selector of Error(string)
+	return testDataError{data: hexutil.Encode(append([]byte{0x08, 0xc3, 0x79, 0xa0}, args...))}
+}
+
+// fakeExecutionSimulator executes calls that use at most gasUsed gas for their execution, and reverts
+// with revertErr if set regardless of the gas provided
+type fakeExecutionSimulator struct {
+	code      []byte
+	gasUsed   uint64
+	revertErr error
+
+	overrides *map[common.Address]subnetevmclient.OverrideAccount
+	from      common.Address
+}
+
+func (s *fakeExecutionSimulator) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return s.code, nil
+}
+
+func (s *fakeExecutionSimulator) EstimateGas(_ context.Context, msg interfaces.CallMsg) (uint64, error) {
+	if s.revertErr != nil {
+		return 0, s.revertErr
+	}
+	return s.gasUsed + intrinsicGas(msg.Data), nil
+}
+
+func (s *fakeExecutionSimulator) CallContract(
+	_ context.Context,
+	msg interfaces.CallMsg,
+	_ *big.Int,
+	overrides *map[common.Address]subnetevmclient.OverrideAccount,
+) ([]byte, error) {
+	s.overrides = overrides
+	s.from = msg.From
+	if s.revertErr != nil {
+		return nil, s.revertErr
+	}
+	if msg.Gas-intrinsicGas(msg.Data) < s.gasUsed {
+		return nil, fmt.Errorf("out of gas")
+	}
+	return nil, nil
+}
+
+func TestSimulateMessageExecution(t *testing.T) {
+	teleporterAddress := common.HexToAddress("0x1234")
+	message := createTestTeleporterMessage()
+	message.RequiredGasLimit = big.NewInt(100_000)
+
+	var tests = []struct {
+		name      string
+		simulator *fakeExecutionSimulator
+		expected  simulateOutput
+	}{
+		{
+			name:      "executes",
+			simulator: &fakeExecutionSimulator{code: []byte{1}, gasUsed: 60_000},
+			expected: simulateOutput{
+				GasUsed:       "60000",
+				SufficientGas: true,
+				Executes:      true,
+			},
+		},
+		{
+			name:      "insufficient gas",
+			simulator: &fakeExecutionSimulator{code: []byte{1}, gasUsed: 150_000},
+			expected: simulateOutput{
+				GasUsed:       "150000",
+				SufficientGas: false,
+				Executes:      false,
+				RevertReason:  "out of gas",
+			},
+		},
+		{
+			name: "reverts",
+			simulator: &fakeExecutionSimulator{
+				code:      []byte{1},
+				revertErr: newTestRevertError(t, "ExampleApp: invalid sender"),
+			},
+			expected: simulateOutput{
+				Executes:     false,
+				RevertReason: "ExampleApp: invalid sender",
+			},
+		},
+		{
+			name:      "no contract code",
+			simulator: &fakeExecutionSimulator{},
+			expected: simulateOutput{
+				RevertReason: "destination address has no contract code, the message would be stored as failed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := simulateMessageExecution(context.Background(), tt.simulator, teleporterAddress, ids.ID{9}, &message)
+			require.NoError(t, err)
+
+			tt.expected.SourceBlockchainID = ids.ID{9}.String()
+			tt.expected.MessageNonce = "1"
+			tt.expected.DestinationAddress = message.DestinationAddress.Hex()
+			tt.expected.RequiredGasLimit = "100000"
+			require.Equal(t, tt.expected, *out)
+
+			if len(tt.simulator.code) > 0 {
+				require.Equal(t, teleporterAddress, tt.simulator.from)
+				require.Equal(t, simulatedTeleporterBalance, (*tt.simulator.overrides)[teleporterAddress].Balance)
+			}
+		})
+	}
+}
+
+func TestReceiptTeleporterMessages(t *testing.T) {
+	teleporter := common.HexToAddress("0x1234")
+	prevTeleporterAddress := teleporterAddress
+	teleporterAddress = teleporter
+	t.Cleanup(func() { teleporterAddress = prevTeleporterAddress })
+
+	message := createTestTeleporterMessage()
+	messageBytes, err := teleportermessenger.PackTeleporterMessage(message)
+	require.NoError(t, err)
+
+	// A receipt mixing a Warp message of another contract, whose payload is not a Teleporter message,
+	// with a Teleporter message and a log of another contract
+	receipt := &types.Receipt{Logs: []*types.Log{
+		newTestWarpLog(t, ids.ID{9}, common.HexToAddress("0xabcd"), []byte{1, 2, 3}),
+		{Address: common.HexToAddress("0xabcd")},
+		newTestWarpLog(t, ids.ID{9}, teleporter, messageBytes),
+	}}
+	messages, err := receiptTeleporterMessages(receipt)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, ids.ID{9}, messages[0].sourceBlockchainID)
+	require.Equal(t, message, *messages[0].message)
+
+	// A Warp message sent by the TeleporterMessenger contract must contain a Teleporter message
+	receipt.Logs = append(receipt.Logs, newTestWarpLog(t, ids.ID{9}, teleporter, []byte{1, 2, 3}))
+	_, err = receiptTeleporterMessages(receipt)
+	require.Error(t, err)
+}
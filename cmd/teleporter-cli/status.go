@@ -0,0 +1,177 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// messageState holds the on-chain state of a message on its source and destination chains
+type messageState struct {
+	// Set on the source chain when the message is sent, and cleared once its receipt is received
+	messageHash common.Hash
+	feeInfo     teleportermessenger.TeleporterFeeInfo
+	// Set on the destination chain when the message is received
+	received             bool
+	relayerRewardAddress common.Address
+	// Set on the destination chain when the message fails to execute, and cleared once it is retried successfully
+	failedMessageHash common.Hash
+}
+
+type statusOutput struct {
+	MessageID            string         `json:"messageID" yaml:"messageID"`
+	Status               string         `json:"status" yaml:"status"`
+	MessageHash          string         `json:"messageHash,omitempty" yaml:"messageHash,omitempty"`
+	FeeInfo              *feeInfoOutput `json:"feeInfo,omitempty" yaml:"feeInfo,omitempty"`
+	Received             bool           `json:"received" yaml:"received"`
+	RelayerRewardAddress string         `json:"relayerRewardAddress,omitempty" yaml:"relayerRewardAddress,omitempty"`
+	FailedMessageHash    string         `json:"failedMessageHash,omitempty" yaml:"failedMessageHash,omitempty"`
+}
+
+var statusCmd = &cobra.Command{
+	Use: "status --source-rpc RPC_URL --destination-rpc RPC_URL --teleporter-address CONTRACT_ADDRESS " +
+		"MESSAGE_ID",
+	Short: "Queries the on-chain state of a Teleporter message",
+	Long: `Given a Teleporter message ID, this command queries the TeleporterMessenger
+contracts on the source and destination chains for the state of the message:
+getMessageHash and getFeeInfo on the source chain, and messageReceived,
+getRelayerRewardAddress and the failed message hash on the destination chain.
+It then summarises whether the message is pending, delivered, executed, failed,
+or receipted back to the source chain. Unlike trace, no logs are queried.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		messageID, err := parseID(args[0])
+		cobra.CheckErr(err)
+
+		state, err := queryMessageState(context.Background(), messageID)
+		cobra.CheckErr(err)
+
+		out := newStatusOutput(messageID, state)
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, out))
+			return
+		}
+		logger.Info("Message state",
+			zap.String("messageID", out.MessageID),
+			zap.String("messageHash", out.MessageHash),
+			zap.Any("feeInfo", out.FeeInfo),
+			zap.Bool("received", out.Received),
+			zap.String("relayerRewardAddress", out.RelayerRewardAddress),
+			zap.String("failedMessageHash", out.FailedMessageHash))
+		cmd.Printf("Status: %s\n", out.Status)
+		cmd.Println("Status command ran successfully")
+	},
+}
+
+// queryMessageState reads the state of the message from the source and destination TeleporterMessenger contracts
+func queryMessageState(ctx context.Context, messageID ids.ID) (messageState, error) {
+	var state messageState
+	opts := &bind.CallOpts{Context: ctx}
+
+	source, err := teleportermessenger.NewTeleporterMessengerCaller(teleporterAddress, sourceClient)
+	if err != nil {
+		return messageState{}, err
+	}
+	state.messageHash, err = source.GetMessageHash(opts, messageID)
+	if err != nil {
+		return messageState{}, err
+	}
+	state.feeInfo.FeeTokenAddress, state.feeInfo.Amount, err = source.GetFeeInfo(opts, messageID)
+	if err != nil {
+		return messageState{}, err
+	}
+
+	state.received, err = messageReceived(ctx, destinationClient, messageID)
+	if err != nil {
+		return messageState{}, err
+	}
+	if !state.received {
+		return state, nil
+	}
+	destination, err := teleportermessenger.NewTeleporterMessengerCaller(teleporterAddress, destinationClient)
+	if err != nil {
+		return messageState{}, err
+	}
+	// getRelayerRewardAddress reverts if the message has not been received
+	state.relayerRewardAddress, err = destination.GetRelayerRewardAddress(opts, messageID)
+	if err != nil {
+		return messageState{}, err
+	}
+	state.failedMessageHash, err = destination.ReceivedFailedMessageHashes(opts, messageID)
+	if err != nil {
+		return messageState{}, err
+	}
+	return state, nil
+}
+
+// messageReceived calls messageReceived on the TeleporterMessenger contract
+func messageReceived(ctx context.Context, client ethclient.Client, messageID ids.ID) (bool, error) {
+	data, err := teleportermessenger.PackMessageReceived(messageID)
+	if err != nil {
+		return false, err
+	}
+	result, err := client.CallContract(ctx, interfaces.CallMsg{
+		To:   &teleporterAddress,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return false, err
+	}
+	return teleportermessenger.UnpackMessageReceivedResult(result)
+}
+
+// messageStatus summarises the state of the message.
+// A receipt is sent back to the source chain whether or not the message executed successfully,
+// so a failed message may also be receipted.
+func messageStatus(state messageState) string {
+	sent := state.messageHash != (common.Hash{})
+	failed := state.failedMessageHash != (common.Hash{})
+	switch {
+	case !state.received && sent:
+		return "pending: awaiting delivery to destination chain"
+	case !state.received:
+		return "not found"
+	case failed && sent:
+		return "failed: execution failed, awaiting retryMessageExecution on destination chain"
+	case failed:
+		return "failed: execution failed and receipted back, awaiting retryMessageExecution on destination chain"
+	case sent:
+		return "executed: awaiting receipt on source chain"
+	default:
+		return "receipted: receipt received on source chain"
+	}
+}
+
+func newStatusOutput(messageID ids.ID, state messageState) statusOutput {
+	out := statusOutput{
+		MessageID: messageID.String(),
+		Status:    messageStatus(state),
+		Received:  state.received,
+	}
+	if state.messageHash != (common.Hash{}) {
+		out.MessageHash = state.messageHash.Hex()
+		out.FeeInfo = newFeeInfoOutput(state.feeInfo)
+	}
+	if state.received {
+		out.RelayerRewardAddress = state.relayerRewardAddress.Hex()
+	}
+	if state.failedMessageHash != (common.Hash{}) {
+		out.FailedMessageHash = state.failedMessageHash.Hex()
+	}
+	return out
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	addSourceDestinationFlags(statusCmd)
+}
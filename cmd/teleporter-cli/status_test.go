@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "no args",
+			args: []string{"status"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "help",
+			args: []string{"status", "--help"},
+			err:  nil,
+			out:  "Given a Teleporter message ID, this command queries the TeleporterMessenger",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestMessageStatus(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	var tests = []struct {
+		name   string
+		state  messageState
+		status string
+	}{
+		{
+			name:   "not found",
+			state:  messageState{},
+			status: "not found",
+		},
+		{
+			name:   "pending",
+			state:  messageState{messageHash: hash},
+			status: "pending: awaiting delivery to destination chain",
+		},
+		{
+			name:   "executed",
+			state:  messageState{messageHash: hash, received: true},
+			status: "executed: awaiting receipt on source chain",
+		},
+		{
+			name:   "failed",
+			state:  messageState{messageHash: hash, received: true, failedMessageHash: hash},
+			status: "failed: execution failed, awaiting retryMessageExecution on destination chain",
+		},
+		{
+			name:  "failed receipted",
+			state: messageState{received: true, failedMessageHash: hash},
+			status: "failed: execution failed and receipted back, " +
+				"awaiting retryMessageExecution on destination chain",
+		},
+		{
+			name:   "receipted",
+			state:  messageState{received: true},
+			status: "receipted: receipt received on source chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.status, messageStatus(tt.state))
+		})
+	}
+}
@@ -0,0 +1,325 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	sourceChain      = "source"
+	destinationChain = "destination"
+)
+
+var (
+	sourceTxHash         string
+	sourceFromBlock      uint64
+	destinationFromBlock uint64
+)
+
+// traceEntry is a single Teleporter event in the lifecycle of a message
+type traceEntry struct {
+	chain     string
+	timestamp uint64
+	log       types.Log
+	event     teleportermessenger.Event
+	relayer   common.Address
+	value     interface{}
+}
+
+type traceEntryOutput struct {
+	Chain     string       `json:"chain" yaml:"chain"`
+	Timestamp uint64       `json:"timestamp" yaml:"timestamp"`
+	Relayer   string       `json:"relayer,omitempty" yaml:"relayer,omitempty"`
+	Event     *eventOutput `json:"event" yaml:"event"`
+}
+
+type traceOutput struct {
+	MessageID string             `json:"messageID" yaml:"messageID"`
+	Stage     string             `json:"stage" yaml:"stage"`
+	Timeline  []traceEntryOutput `json:"timeline" yaml:"timeline"`
+}
+
+var traceCmd = &cobra.Command{
+	Use: "trace --source-rpc RPC_URL --destination-rpc RPC_URL --teleporter-address CONTRACT_ADDRESS " +
+		"(MESSAGE_ID | --source-tx TRANSACTION_HASH)",
+	Short: "Traces a Teleporter message across its source and destination chains",
+	Long: `Given a Teleporter message ID, or the hash of the transaction that sent the message,
+this command queries the Teleporter logs on both the source and destination chains
+and reconstructs the lifecycle of the message: SendCrossChainMessage, AddFeeAmount,
+ReceiveCrossChainMessage, MessageExecuted or MessageExecutionFailed, and the
+ReceiptReceived event that is eventually emitted back on the source chain.
+The events are printed as a timeline along with the block numbers and relayer
+addresses, followed by the stage the message is currently in.`,
+	Args: traceArgs,
+	Run:  traceRun,
+}
+
+func traceArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+		return err
+	}
+	if (len(args) == 1) == (sourceTxHash != "") {
+		return fmt.Errorf("exactly one of MESSAGE_ID or --source-tx must be provided")
+	}
+	return nil
+}
+
+func traceRun(cmd *cobra.Command, args []string) {
+	var messageIDs []ids.ID
+	if len(args) == 1 {
+		messageID, err := parseID(args[0])
+		cobra.CheckErr(err)
+		messageIDs = append(messageIDs, messageID)
+	} else {
+		sentMessageIDs, fromBlock, err := messageIDsFromSourceTx(common.HexToHash(sourceTxHash))
+		cobra.CheckErr(err)
+		messageIDs = sentMessageIDs
+		if sourceFromBlock == 0 {
+			sourceFromBlock = fromBlock
+		}
+	}
+
+	var traceOuts []traceOutput
+	for _, messageID := range messageIDs {
+		entries, err := traceMessage(messageID)
+		cobra.CheckErr(err)
+		if !isTableOutput() {
+			traceOut, err := newTraceOutput(messageID, entries)
+			cobra.CheckErr(err)
+			traceOuts = append(traceOuts, traceOut)
+			continue
+		}
+		printTimeline(cmd, messageID, entries)
+	}
+	if !isTableOutput() {
+		cobra.CheckErr(printOutput(cmd, traceOuts))
+		return
+	}
+	cmd.Println("Trace command ran successfully")
+}
+
+// messageIDsFromSourceTx returns the IDs of the Teleporter messages sent in the given transaction,
+// as well as the block the transaction was included in. Each message ID is recomputed from the message
+// nonce and blockchain IDs to verify it matches the one emitted in the SendCrossChainMessage event.
+func messageIDsFromSourceTx(txHash common.Hash) ([]ids.ID, uint64, error) {
+	receipt, err := sourceClient.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	caller, err := teleportermessenger.NewTeleporterMessengerCaller(teleporterAddress, sourceClient)
+	if err != nil {
+		return nil, 0, err
+	}
+	sourceBlockchainID, err := caller.BlockchainID(&bind.CallOpts{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var messageIDs []ids.ID
+	for _, log := range receipt.Logs {
+		if log.Address != teleporterAddress {
+			continue
+		}
+		out, err := teleportermessenger.DecodeLog(*log)
+		if err != nil {
+			return nil, 0, err
+		}
+		sendEvent, ok := out.(*teleportermessenger.TeleporterMessengerSendCrossChainMessage)
+		if !ok {
+			continue
+		}
+		messageID, err := teleportermessenger.CalculateTeleporterMessageID(
+			teleporterAddress, sourceBlockchainID, sendEvent.Message)
+		if err != nil {
+			return nil, 0, err
+		}
+		if messageID != ids.ID(sendEvent.MessageID) {
+			return nil, 0, fmt.Errorf("calculated message ID %s does not match emitted message ID %s",
+				messageID, ids.ID(sendEvent.MessageID))
+		}
+		messageIDs = append(messageIDs, messageID)
+	}
+	if len(messageIDs) == 0 {
+		return nil, 0, fmt.Errorf("no SendCrossChainMessage event found in transaction %s", txHash.Hex())
+	}
+	return messageIDs, receipt.BlockNumber.Uint64(), nil
+}
+
+// traceMessage collects the Teleporter events for the message on both the source and destination chains,
+// ordered by block timestamp.
+func traceMessage(messageID ids.ID) ([]traceEntry, error) {
+	sourceEntries, err := collectTraceEntries(sourceClient, sourceChain, messageID, sourceFromBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source chain: %w", err)
+	}
+	destinationEntries, err := collectTraceEntries(
+		destinationClient, destinationChain, messageID, destinationFromBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query destination chain: %w", err)
+	}
+
+	entries := append(sourceEntries, destinationEntries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].timestamp != entries[j].timestamp {
+			return entries[i].timestamp < entries[j].timestamp
+		}
+		if entries[i].chain != entries[j].chain {
+			return entries[i].chain == sourceChain
+		}
+		return entries[i].log.Index < entries[j].log.Index
+	})
+	return entries, nil
+}
+
+// collectTraceEntries queries the Teleporter logs indexed by the message ID on a single chain.
+// All message related Teleporter events index the message ID as their first topic.
+func collectTraceEntries(
+	client ethclient.Client,
+	chain string,
+	messageID ids.ID,
+	fromBlock uint64,
+) ([]traceEntry, error) {
+	logs, err := client.FilterLogs(context.Background(), interfaces.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		Addresses: []common.Address{teleporterAddress},
+		Topics:    [][]common.Hash{{}, {common.Hash(messageID)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timestamps := make(map[uint64]uint64)
+	var entries []traceEntry
+	for _, log := range logs {
+		out, err := teleportermessenger.DecodeLog(log)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("Parsed Teleporter event",
+			zap.String("chain", chain),
+			zap.String("name", out.Kind().String()),
+			zap.Any("event", out))
+
+		timestamp, ok := timestamps[log.BlockNumber]
+		if !ok {
+			header, err := client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(log.BlockNumber))
+			if err != nil {
+				return nil, err
+			}
+			timestamp = header.Time
+			timestamps[log.BlockNumber] = timestamp
+		}
+
+		entries = append(entries, traceEntry{
+			chain:     chain,
+			timestamp: timestamp,
+			log:       log,
+			event:     out.Kind(),
+			relayer:   relayerAddress(out),
+			value:     out,
+		})
+	}
+	return entries, nil
+}
+
+// relayerAddress returns the relayer address associated with the event, if any
+func relayerAddress(event interface{}) common.Address {
+	switch e := event.(type) {
+	case *teleportermessenger.TeleporterMessengerReceiveCrossChainMessage:
+		return e.Deliverer
+	case *teleportermessenger.TeleporterMessengerReceiptReceived:
+		return e.RelayerRewardAddress
+	default:
+		return common.Address{}
+	}
+}
+
+// traceStage summarizes where in its lifecycle a message is, given its traced events
+func traceStage(entries []traceEntry) string {
+	seen := make(map[teleportermessenger.Event]bool)
+	for _, entry := range entries {
+		seen[entry.event] = true
+	}
+	// The receipt of a message is sent back whether or not its execution succeeded, so a failed message
+	// that has not been retried is reported as failed even if its receipt was received.
+	failed := seen[teleportermessenger.MessageExecutionFailed] && !seen[teleportermessenger.MessageExecuted]
+	switch {
+	case failed && seen[teleportermessenger.ReceiptReceived]:
+		return "execution failed: receipt received on source chain, awaiting retryMessageExecution on destination chain"
+	case failed:
+		return "execution failed: awaiting retryMessageExecution on destination chain"
+	case seen[teleportermessenger.ReceiptReceived]:
+		return "complete: receipt received on source chain"
+	case seen[teleportermessenger.MessageExecuted]:
+		return "executed: awaiting receipt on source chain"
+	case seen[teleportermessenger.ReceiveCrossChainMessage]:
+		return "delivered: awaiting execution on destination chain"
+	case seen[teleportermessenger.SendCrossChainMessage]:
+		return "sent: awaiting delivery to destination chain"
+	default:
+		return "not found"
+	}
+}
+
+func newTraceOutput(messageID ids.ID, entries []traceEntry) (traceOutput, error) {
+	out := traceOutput{
+		MessageID: messageID.String(),
+		Stage:     traceStage(entries),
+		Timeline:  []traceEntryOutput{},
+	}
+	for _, entry := range entries {
+		eventOut, err := newEventOutput(entry.value, &entry.log)
+		if err != nil {
+			return traceOutput{}, err
+		}
+		entryOut := traceEntryOutput{
+			Chain:     entry.chain,
+			Timestamp: entry.timestamp,
+			Event:     eventOut,
+		}
+		if entry.relayer != (common.Address{}) {
+			entryOut.Relayer = entry.relayer.Hex()
+		}
+		out.Timeline = append(out.Timeline, entryOut)
+	}
+	return out, nil
+}
+
+func printTimeline(cmd *cobra.Command, messageID ids.ID, entries []traceEntry) {
+	cmd.Printf("Message %s (%s)\n", messageID, common.Hash(messageID).Hex())
+	for _, entry := range entries {
+		line := fmt.Sprintf("  [%-11s] block %d tx %s %s",
+			entry.chain, entry.log.BlockNumber, entry.log.TxHash.Hex(), entry.event)
+		if entry.relayer != (common.Address{}) {
+			line += " relayer " + entry.relayer.Hex()
+		}
+		cmd.Println(line)
+	}
+	cmd.Println("Stage:", traceStage(entries))
+}
+
+func init() {
+	rootCmd.AddCommand(traceCmd)
+	addSourceDestinationFlags(traceCmd)
+	traceCmd.Flags().StringVar(&sourceTxHash, "source-tx", "", "Hash of the transaction that sent the message")
+	traceCmd.Flags().Uint64Var(&sourceFromBlock, "source-from-block", 0,
+		"Block to start searching from on the source chain")
+	traceCmd.Flags().Uint64Var(&destinationFromBlock, "destination-from-block", 0,
+		"Block to start searching from on the destination chain")
+}
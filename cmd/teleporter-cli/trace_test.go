@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "no args",
+			args: []string{"trace"},
+			err:  fmt.Errorf("exactly one of MESSAGE_ID or --source-tx must be provided"),
+		},
+		{
+			name: "help",
+			args: []string{"trace", "--help"},
+			err:  nil,
+			out:  "Given a Teleporter message ID, or the hash of the transaction that sent the message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestTraceStage(t *testing.T) {
+	var tests = []struct {
+		name   string
+		events []teleportermessenger.Event
+		stage  string
+	}{
+		{
+			name:   "not found",
+			events: nil,
+			stage:  "not found",
+		},
+		{
+			name:   "sent",
+			events: []teleportermessenger.Event{teleportermessenger.SendCrossChainMessage},
+			stage:  "sent: awaiting delivery to destination chain",
+		},
+		{
+			name: "execution failed",
+			events: []teleportermessenger.Event{
+				teleportermessenger.SendCrossChainMessage,
+				teleportermessenger.ReceiveCrossChainMessage,
+				teleportermessenger.MessageExecutionFailed,
+			},
+			stage: "execution failed: awaiting retryMessageExecution on destination chain",
+		},
+		{
+			name: "retried",
+			events: []teleportermessenger.Event{
+				teleportermessenger.SendCrossChainMessage,
+				teleportermessenger.ReceiveCrossChainMessage,
+				teleportermessenger.MessageExecutionFailed,
+				teleportermessenger.MessageExecuted,
+			},
+			stage: "executed: awaiting receipt on source chain",
+		},
+		{
+			name: "failed and receipted",
+			events: []teleportermessenger.Event{
+				teleportermessenger.SendCrossChainMessage,
+				teleportermessenger.ReceiveCrossChainMessage,
+				teleportermessenger.MessageExecutionFailed,
+				teleportermessenger.ReceiptReceived,
+			},
+			stage: "execution failed: receipt received on source chain, awaiting retryMessageExecution on destination chain",
+		},
+		{
+			name: "retried and receipted",
+			events: []teleportermessenger.Event{
+				teleportermessenger.SendCrossChainMessage,
+				teleportermessenger.ReceiveCrossChainMessage,
+				teleportermessenger.MessageExecutionFailed,
+				teleportermessenger.ReceiptReceived,
+				teleportermessenger.MessageExecuted,
+			},
+			stage: "complete: receipt received on source chain",
+		},
+		{
+			name: "complete",
+			events: []teleportermessenger.Event{
+				teleportermessenger.SendCrossChainMessage,
+				teleportermessenger.ReceiveCrossChainMessage,
+				teleportermessenger.MessageExecuted,
+				teleportermessenger.ReceiptReceived,
+			},
+			stage: "complete: receipt received on source chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []traceEntry
+			for _, event := range tt.events {
+				entries = append(entries, traceEntry{event: event})
+			}
+			require.Equal(t, tt.stage, traceStage(entries))
+		})
+	}
+}
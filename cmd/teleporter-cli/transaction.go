@@ -5,10 +5,10 @@ package main
 
 import (
 	"context"
+	"math/big"
 
-	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/ethclient"
-	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
 	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
@@ -25,62 +25,147 @@ var (
 	client            ethclient.Client
 )
 
+type warpMessageOutput struct {
+	WarpMessageID      string                   `json:"warpMessageID" yaml:"warpMessageID"`
+	SourceBlockchainID string                   `json:"sourceBlockchainID" yaml:"sourceBlockchainID"`
+	SourceAddress      string                   `json:"sourceAddress" yaml:"sourceAddress"`
+	TeleporterVersion  string                   `json:"teleporterVersion,omitempty" yaml:"teleporterVersion,omitempty"`
+	Message            *teleporterMessageOutput `json:"message" yaml:"message"`
+}
+
+type transactionOutput struct {
+	TransactionHash string              `json:"transactionHash" yaml:"transactionHash"`
+	Events          []*eventOutput      `json:"events" yaml:"events"`
+	WarpMessages    []warpMessageOutput `json:"warpMessages" yaml:"warpMessages"`
+}
+
 var transactionCmd = &cobra.Command{
-	Use:   "transaction --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS TRANSACTION_HASH",
+	Use:   "transaction --rpc RPC_URL (--teleporter-address CONTRACT_ADDRESS | --registry REGISTRY_ADDRESS) TRANSACTION_HASH",
 	Short: "Parses relevant Teleporter logs from a transaction",
 	Long: `Given a transaction this command looks through the transaction's receipt
 for Teleporter and Warp log events. When corresponding log events are found,
-the command parses to log event fields to a more human readable format.`,
+the command parses to log event fields to a more human readable format.
+If --registry is provided, the logs of every Teleporter version registered in the
+TeleporterRegistry contract are parsed and labelled with their protocol version.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		receipt, err := client.TransactionReceipt(context.Background(),
-			common.HexToHash(args[0]))
+		ctx := context.Background()
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(args[0]))
 		cobra.CheckErr(err)
 
-		for _, log := range receipt.Logs {
-			if log.Address == teleporterAddress {
-				logger.Info("Processing Teleporter log", zap.Any("log", log))
+		versions, err := teleporterVersions(ctx)
+		cobra.CheckErr(err)
+		txOut, err := newTransactionOutput(receipt, versions)
+		cobra.CheckErr(err)
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, txOut))
+			return
+		}
+		cmd.Println("Transaction command ran successfully")
+	},
+}
 
-				event, err := teleporterABI.EventByID(log.Topics[0])
-				cobra.CheckErr(err)
+// teleporterVersions returns the Teleporter contract addresses whose logs are decoded, mapped to their
+// protocol version. If --registry is provided every version registered in it is included, otherwise only
+// the --teleporter-address contract, whose version is unknown.
+func teleporterVersions(ctx context.Context) (map[common.Address]*big.Int, error) {
+	versions := map[common.Address]*big.Int{}
+	if registryAddress != "" {
+		address, err := parseAddress(registryAddress)
+		if err != nil {
+			return nil, err
+		}
+		registry, err := newRegistryCaller(address, client)
+		if err != nil {
+			return nil, err
+		}
+		versions, err = registeredTeleporterVersions(ctx, registry)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := versions[teleporterAddress]; !ok {
+		versions[teleporterAddress] = nil
+	}
+	return versions, nil
+}
 
-				out, err := teleportermessenger.FilterTeleporterEvents(log.Topics, log.Data, event.Name)
-				cobra.CheckErr(err)
-				logger.Info("Parsed Teleporter event", zap.String("name", event.Name), zap.Any("event", out))
+// newTransactionOutput decodes the logs in the receipt emitted by any of the Teleporter contract versions,
+// and the Warp messages they sent, labelling each with the version of the contract that emitted it.
+func newTransactionOutput(receipt *types.Receipt, versions map[common.Address]*big.Int) (transactionOutput, error) {
+	txOut := transactionOutput{
+		TransactionHash: receipt.TxHash.Hex(),
+		Events:          []*eventOutput{},
+		WarpMessages:    []warpMessageOutput{},
+	}
+	for _, log := range receipt.Logs {
+		if version, ok := versions[log.Address]; ok {
+			logger.Info("Processing Teleporter log",
+				zap.String("teleporterVersion", versionOutput(version)),
+				zap.Any("log", log))
+
+			out, err := teleportermessenger.DecodeLog(*log)
+			if err != nil {
+				return transactionOutput{}, err
 			}
+			logger.Info("Parsed Teleporter event", zap.String("name", out.Kind().String()), zap.Any("event", out))
 
-			if log.Address == common.HexToAddress(warpPrecompileAddress) {
-				logger.Debug("Processing Warp log", zap.Any("log", log))
-
-				unsignedMsg, err := warp.UnpackSendWarpEventDataToMessage(log.Data)
-				cobra.CheckErr(err)
+			eventOut, err := newEventOutput(out, log)
+			if err != nil {
+				return transactionOutput{}, err
+			}
+			eventOut.TeleporterVersion = versionOutput(version)
+			txOut.Events = append(txOut.Events, eventOut)
+		}
 
-				warpPayload, err := warpPayload.ParseAddressedCall(unsignedMsg.Payload)
-				cobra.CheckErr(err)
+		if log.Address == common.HexToAddress(warpPrecompileAddress) {
+			logger.Debug("Processing Warp log", zap.Any("log", log))
 
-				teleporterMessage, err := teleportermessenger.UnpackTeleporterMessage(warpPayload.Payload)
-				cobra.CheckErr(err)
-				logger.Info("Parsed Teleporter message",
-					zap.String("warpMessageID", unsignedMsg.ID().Hex()),
-					zap.String("teleporterMessageNonce", teleporterMessage.MessageNonce.String()),
-					zap.Any("message", teleporterMessage))
+			// Warp messages sent by other contracts do not contain a Teleporter message, so they are skipped
+			// before their payload is unpacked
+			unsignedMsg, addressedCall, err := parseAddressedCallWarpLog(*log)
+			if err != nil {
+				return transactionOutput{}, err
+			}
+			sourceAddress := common.BytesToAddress(addressedCall.SourceAddress)
+			if _, ok := versions[sourceAddress]; !ok {
+				logger.Debug("Skipping Warp message not sent by Teleporter",
+					zap.String("warpMessageID", unsignedMsg.ID().String()))
+				continue
 			}
+			teleporterMessage, err := teleportermessenger.UnpackTeleporterMessage(addressedCall.Payload)
+			if err != nil {
+				return transactionOutput{}, err
+			}
+			logger.Info("Parsed Teleporter message",
+				zap.String("warpMessageID", unsignedMsg.ID().Hex()),
+				zap.String("teleporterMessageNonce", teleporterMessage.MessageNonce.String()),
+				zap.String("teleporterVersion", versionOutput(versions[sourceAddress])),
+				zap.Any("message", teleporterMessage))
+
+			txOut.WarpMessages = append(txOut.WarpMessages, warpMessageOutput{
+				WarpMessageID:      unsignedMsg.ID().String(),
+				SourceBlockchainID: unsignedMsg.SourceChainID.String(),
+				SourceAddress:      sourceAddress.Hex(),
+				TeleporterVersion:  versionOutput(versions[sourceAddress]),
+				Message:            newTeleporterMessageOutput(*teleporterMessage),
+			})
 		}
-		cmd.Println("Transaction command ran successfully")
-	},
+	}
+	return txOut, nil
+}
+
+// versionOutput returns the decimal Teleporter version, or an empty string if the version is unknown
+func versionOutput(version *big.Int) string {
+	if version == nil {
+		return ""
+	}
+	return version.String()
 }
 
 func init() {
 	rootCmd.AddCommand(transactionCmd)
-	transactionCmd.PersistentFlags().StringVar(&rpcEndpoint, "rpc", "", "RPC endpoint to connect to the node")
-	address := transactionCmd.PersistentFlags().StringP("teleporter-address", "t", "", "Teleporter contract address")
-	err := transactionCmd.MarkPersistentFlagRequired("rpc")
-	cobra.CheckErr(err)
-	err = transactionCmd.MarkPersistentFlagRequired("teleporter-address")
-	cobra.CheckErr(err)
-	transactionCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		return transactionPreRunE(cmd, args, address)
-	}
+	addRPCFlags(transactionCmd)
 }
 
 func transactionPreRunE(cmd *cobra.Command, args []string, address *string) error {
@@ -88,12 +173,46 @@ func transactionPreRunE(cmd *cobra.Command, args []string, address *string) erro
 	if err := callPersistentPreRunE(cmd, args); err != nil {
 		return err
 	}
-	teleporterAddress = common.HexToAddress(*address)
+	if err := setChainFlags(cmd, chainName, "rpc", "teleporter-address"); err != nil {
+		return err
+	}
 	c, err := ethclient.Dial(rpcEndpoint)
 	if err != nil {
 		return err
 	}
-
 	client = c
-	return err
+
+	// An explicit Teleporter address takes precedence over the registry
+	if registryAddress != "" && !cmd.Flags().Changed("teleporter-address") {
+		resolved, err := resolveRegistryTeleporterAddress(context.Background())
+		if err != nil {
+			return err
+		}
+		if err := cmd.Flags().Set("teleporter-address", resolved.Hex()); err != nil {
+			return err
+		}
+	}
+	teleporterAddress = common.HexToAddress(*address)
+	return nil
+}
+
+// resolveRegistryTeleporterAddress looks up the --teleporter-version of the Teleporter contract in the
+// --registry contract, or its latest version if no version is provided.
+func resolveRegistryTeleporterAddress(ctx context.Context) (common.Address, error) {
+	address, err := parseAddress(registryAddress)
+	if err != nil {
+		return common.Address{}, err
+	}
+	registry, err := newRegistryCaller(address, client)
+	if err != nil {
+		return common.Address{}, err
+	}
+	resolved, err := resolveTeleporterAddress(ctx, registry, teleporterVersion)
+	if err != nil {
+		return common.Address{}, err
+	}
+	logger.Info("Resolved Teleporter address from registry",
+		zap.String("registryAddress", address.Hex()),
+		zap.String("teleporterAddress", resolved.Hex()))
+	return resolved, nil
 }
@@ -2,8 +2,13 @@ package main
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,3 +44,77 @@ func TestTransactionCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTransactionOutput(t *testing.T) {
+	messageExecuted := teleporterABI.Events["MessageExecuted"].ID
+	newLog := func(address common.Address, messageID common.Hash) *types.Log {
+		return &types.Log{
+			Address: address,
+			Topics:  []common.Hash{messageExecuted, messageID, common.HexToHash("0xbb")},
+		}
+	}
+	v1, v2, other := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	receipt := &types.Receipt{
+		TxHash: common.HexToHash("0xabcd"),
+		Logs: []*types.Log{
+			newLog(v1, common.HexToHash("0x01")),
+			newLog(other, common.HexToHash("0x03")),
+			newLog(v2, common.HexToHash("0x02")),
+		},
+	}
+
+	var tests = []struct {
+		name     string
+		versions map[common.Address]*big.Int
+		expected map[string]string
+	}{
+		{
+			name:     "single address",
+			versions: map[common.Address]*big.Int{v1: nil},
+			expected: map[string]string{v1.Hex(): ""},
+		},
+		{
+			name:     "registered versions",
+			versions: map[common.Address]*big.Int{v1: big.NewInt(1), v2: big.NewInt(2)},
+			expected: map[string]string{v1.Hex(): "1", v2.Hex(): "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := newTransactionOutput(receipt, tt.versions)
+			require.NoError(t, err)
+			require.Equal(t, receipt.TxHash.Hex(), out.TransactionHash)
+			require.Len(t, out.Events, len(tt.expected))
+			for _, event := range out.Events {
+				require.Equal(t, teleportermessenger.MessageExecuted.String(), event.Name)
+				version, ok := tt.expected[event.Address]
+				require.True(t, ok, "unexpected event from %s", event.Address)
+				require.Equal(t, version, event.TeleporterVersion)
+			}
+		})
+	}
+}
+
+func TestNewTransactionOutputWarpMessages(t *testing.T) {
+	teleporter, other := common.HexToAddress("0x1"), common.HexToAddress("0x3")
+	message := createTestTeleporterMessage()
+	messageBytes, err := teleportermessenger.PackTeleporterMessage(message)
+	require.NoError(t, err)
+
+	// A Warp message of another contract, whose payload is not a Teleporter message, is skipped
+	receipt := &types.Receipt{
+		TxHash: common.HexToHash("0xabcd"),
+		Logs: []*types.Log{
+			newTestWarpLog(t, ids.ID{9}, other, []byte{1, 2, 3}),
+			newTestWarpLog(t, ids.ID{9}, teleporter, messageBytes),
+		},
+	}
+	out, err := newTransactionOutput(receipt, map[common.Address]*big.Int{teleporter: big.NewInt(1)})
+	require.NoError(t, err)
+	require.Len(t, out.WarpMessages, 1)
+	require.Equal(t, teleporter.Hex(), out.WarpMessages[0].SourceAddress)
+	require.Equal(t, ids.ID{9}.String(), out.WarpMessages[0].SourceBlockchainID)
+	require.Equal(t, "1", out.WarpMessages[0].TeleporterVersion)
+	require.Equal(t, newTeleporterMessageOutput(message), out.WarpMessages[0].Message)
+}
@@ -0,0 +1,144 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/accounts/keystore"
+	"github.com/ava-labs/subnet-evm/core/types"
+	exampleerc20 "github.com/ava-labs/teleporter/abi-bindings/go/Mocks/ExampleERC20"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPrivateKeyEnv       = "TELEPORTER_CLI_PRIVATE_KEY"
+	defaultKeystorePasswordEnv = "TELEPORTER_CLI_KEYSTORE_PASSWORD"
+
+	transactionTimeout = 2 * time.Minute
+)
+
+// contractBackend is the subset of an RPC client needed to read from and send transactions
+// to contracts. It is implemented by ethclient.Client as well as the simulated backend.
+type contractBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// keyFlags holds the flags describing where to load the key used to sign transactions from
+type keyFlags struct {
+	privateKeyEnv       string
+	keystoreFile        string
+	keystorePasswordEnv string
+}
+
+var signerKeyFlags keyFlags
+
+func addKeyFlags(cmd *cobra.Command, f *keyFlags) {
+	cmd.Flags().StringVar(&f.privateKeyEnv, "private-key-env", defaultPrivateKeyEnv,
+		"Environment variable holding the hex encoded private key used to sign transactions")
+	cmd.Flags().StringVar(&f.keystoreFile, "keystore", "",
+		"Encrypted keystore file holding the key used to sign transactions, used instead of --private-key-env")
+	cmd.Flags().StringVar(&f.keystorePasswordEnv, "keystore-password-env", defaultKeystorePasswordEnv,
+		"Environment variable holding the password of the keystore file")
+}
+
+// privateKey loads the signing key from the keystore file if one is provided,
+// and from the private key environment variable otherwise.
+func (f *keyFlags) privateKey() (*ecdsa.PrivateKey, error) {
+	if f.keystoreFile != "" {
+		keyJSON, err := os.ReadFile(f.keystoreFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(keyJSON, os.Getenv(f.keystorePasswordEnv))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt keystore %s: %w", f.keystoreFile, err)
+		}
+		return key.PrivateKey, nil
+	}
+
+	hexKey := os.Getenv(f.privateKeyEnv)
+	if hexKey == "" {
+		return nil, fmt.Errorf("no private key found, set %s or provide --keystore", f.privateKeyEnv)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in %s: %w", f.privateKeyEnv, err)
+	}
+	return key, nil
+}
+
+// newTransactOpts loads the signing key and creates the options to sign transactions for the given chain
+func newTransactOpts(ctx context.Context, f *keyFlags, chainID *big.Int) (*bind.TransactOpts, error) {
+	key, err := f.privateKey()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+	return opts, nil
+}
+
+// waitForSuccess waits for the transaction to be accepted, and returns an error if it reverted
+func waitForSuccess(ctx context.Context, backend bind.DeployBackend, tx *types.Transaction) (*types.Receipt, error) {
+	cctx, cancel := context.WithTimeout(ctx, transactionTimeout)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(cctx, backend, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+// ensureERC20Allowance approves spender to transfer amount of the token on behalf of the sender,
+// if the current allowance is insufficient.
+func ensureERC20Allowance(
+	ctx context.Context,
+	backend contractBackend,
+	opts *bind.TransactOpts,
+	tokenAddress common.Address,
+	spender common.Address,
+	amount *big.Int,
+) error {
+	token, err := exampleerc20.NewExampleERC20(tokenAddress, backend)
+	if err != nil {
+		return err
+	}
+	allowance, err := token.Allowance(&bind.CallOpts{Context: ctx}, opts.From, spender)
+	if err != nil {
+		return fmt.Errorf("failed to get allowance of token %s: %w", tokenAddress.Hex(), err)
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil
+	}
+
+	logger.Info("Approving fee token",
+		zap.String("token", tokenAddress.Hex()),
+		zap.String("spender", spender.Hex()),
+		zap.String("amount", amount.String()))
+	tx, err := token.Approve(opts, spender, amount)
+	if err != nil {
+		return fmt.Errorf("failed to approve token %s: %w", tokenAddress.Hex(), err)
+	}
+	_, err = waitForSuccess(ctx, backend, tx)
+	return err
+}
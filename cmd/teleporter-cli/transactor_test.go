@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind/backends"
+	"github.com/ava-labs/subnet-evm/accounts/keystore"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/utils"
+	exampleerc20 "github.com/ava-labs/teleporter/abi-bindings/go/Mocks/ExampleERC20"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// simulatedChainID is the chain ID always used by the simulated backend
+var simulatedChainID = big.NewInt(1337)
+
+// newTestBackend creates a simulated backend with a funded key. Blocks are committed
+// in the background so that waiting for transactions to be accepted completes.
+func newTestBackend(t *testing.T) (*backends.SimulatedBackend, *ecdsa.PrivateKey) {
+	return newConfiguredTestBackend(t, func(*params.ChainConfig) {})
+}
+
+// newTestWarpBackend creates a simulated backend like newTestBackend, with the Warp precompile enabled and
+// the given blockchain ID, so that the TeleporterMessenger contract can send messages.
+func newTestWarpBackend(t *testing.T, blockchainID ids.ID) (*backends.SimulatedBackend, *ecdsa.PrivateKey) {
+	return newConfiguredTestBackend(t, func(config *params.ChainConfig) {
+		snowCtx := utils.TestSnowContext()
+		snowCtx.ChainID = blockchainID
+		config.AvalancheContext = params.AvalancheContext{SnowCtx: snowCtx}
+		config.GenesisPrecompiles = params.Precompiles{
+			warp.ConfigKey: warp.NewDefaultConfig(utils.NewUint64(0)),
+		}
+	})
+}
+
+// newConfiguredTestBackend creates a simulated backend like newTestBackend, after applying configure to its
+// chain config. The simulated backend is always created with its own copy of params.TestChainConfig, which it
+// reads when processing each block, so the copy is configured before any block is committed.
+func newConfiguredTestBackend(
+	t *testing.T,
+	configure func(config *params.ChainConfig),
+) (*backends.SimulatedBackend, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	balance := new(big.Int).Mul(big.NewInt(1_000), big.NewInt(params.Ether))
+	backend := backends.NewSimulatedBackend(
+		core.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: balance}},
+		params.TestChainConfig.FeeConfig.GasLimit.Uint64(),
+	)
+	configure(backend.Blockchain().Config())
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				backend.Commit(true)
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		backend.Close()
+	})
+	return backend, key
+}
+
+func TestKeyFlagsPrivateKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("TEST_PRIVATE_KEY", common.Bytes2Hex(crypto.FromECDSA(key)))
+		f := keyFlags{privateKeyEnv: "TEST_PRIVATE_KEY"}
+		loaded, err := f.privateKey()
+		require.NoError(t, err)
+		require.Equal(t, key.D, loaded.D)
+	})
+
+	t.Run("missing env", func(t *testing.T) {
+		f := keyFlags{privateKeyEnv: "TEST_MISSING_PRIVATE_KEY"}
+		_, err := f.privateKey()
+		require.ErrorContains(t, err, "no private key found")
+	})
+
+	t.Run("keystore", func(t *testing.T) {
+		keyJSON, err := keystore.EncryptKey(&keystore.Key{
+			Id:         uuid.New(),
+			Address:    crypto.PubkeyToAddress(key.PublicKey),
+			PrivateKey: key,
+		}, "password", keystore.LightScryptN, keystore.LightScryptP)
+		require.NoError(t, err)
+		file := filepath.Join(t.TempDir(), "keystore.json")
+		require.NoError(t, os.WriteFile(file, keyJSON, 0o600))
+
+		t.Setenv("TEST_KEYSTORE_PASSWORD", "password")
+		f := keyFlags{keystoreFile: file, keystorePasswordEnv: "TEST_KEYSTORE_PASSWORD"}
+		loaded, err := f.privateKey()
+		require.NoError(t, err)
+		require.Equal(t, key.D, loaded.D)
+
+		t.Setenv("TEST_KEYSTORE_PASSWORD", "wrong")
+		_, err = f.privateKey()
+		require.ErrorContains(t, err, "failed to decrypt keystore")
+	})
+}
+
+func TestEnsureERC20Allowance(t *testing.T) {
+	ctx := context.Background()
+	backend, key := newTestBackend(t)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, simulatedChainID)
+	require.NoError(t, err)
+	opts.Context = ctx
+
+	tokenAddress, tx, token, err := exampleerc20.DeployExampleERC20(opts, backend)
+	require.NoError(t, err)
+	_, err = waitForSuccess(ctx, backend, tx)
+	require.NoError(t, err)
+
+	spender := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+	amount := big.NewInt(100)
+	require.NoError(t, ensureERC20Allowance(ctx, backend, opts, tokenAddress, spender, amount))
+
+	allowance, err := token.Allowance(&bind.CallOpts{}, opts.From, spender)
+	require.NoError(t, err)
+	require.Equal(t, amount, allowance)
+
+	// A sufficient allowance does not need to be approved again
+	require.NoError(t, ensureERC20Allowance(ctx, backend, opts, tokenAddress, spender, big.NewInt(50)))
+	allowance, err = token.Allowance(&bind.CallOpts{}, opts.From, spender)
+	require.NoError(t, err)
+	require.Equal(t, amount, allowance)
+}
@@ -0,0 +1,177 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sourceRPCEndpoint      string
+	destinationRPCEndpoint string
+	sourceClient           ethclient.Client
+	destinationClient      ethclient.Client
+)
+
+// parseID parses an ID such as a blockchain ID or Teleporter message ID,
+// encoded either as CB58 or as a (optionally 0x prefixed) hex string.
+func parseID(s string) (ids.ID, error) {
+	if id, err := ids.FromString(s); err == nil {
+		return id, nil
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("invalid ID %s: must be CB58 or hex encoded", s)
+	}
+	return ids.ToID(b)
+}
+
+// parseBigInt parses a decimal or 0x prefixed hex encoded integer. An empty string is parsed as zero.
+func parseBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return big.NewInt(0), nil
+	}
+	b, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", s)
+	}
+	return b, nil
+}
+
+// parseAddress parses a hex encoded address. An empty string is parsed as the zero address.
+func parseAddress(s string) (common.Address, error) {
+	if s == "" {
+		return common.Address{}, nil
+	}
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address %s", s)
+	}
+	return common.HexToAddress(s), nil
+}
+
+func parseAddresses(addresses []string) ([]common.Address, error) {
+	parsed := []common.Address{}
+	for _, address := range addresses {
+		a, err := parseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, a)
+	}
+	return parsed, nil
+}
+
+// parseHexBytes parses a (optionally 0x prefixed) hex string. An empty string is parsed as empty bytes.
+func parseHexBytes(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex bytes %s: %w", s, err)
+	}
+	return b, nil
+}
+
+// parseAddressedCallWarpLog parses a log emitted by the Warp precompile into the unsigned Warp message and
+// its AddressedCall payload, whose source address is the contract that sent the message.
+func parseAddressedCallWarpLog(log types.Log) (*avalancheWarp.UnsignedMessage, *warpPayload.AddressedCall, error) {
+	unsignedMsg, err := warp.UnpackSendWarpEventDataToMessage(log.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	addressedCall, err := warpPayload.ParseAddressedCall(unsignedMsg.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return unsignedMsg, addressedCall, nil
+}
+
+// parseTeleporterWarpLog parses a log emitted by the Warp precompile into the unsigned Warp message,
+// its AddressedCall payload, and the Teleporter message contained in the payload.
+func parseTeleporterWarpLog(
+	log types.Log,
+) (*avalancheWarp.UnsignedMessage, *warpPayload.AddressedCall, *teleportermessenger.TeleporterMessage, error) {
+	unsignedMsg, addressedCall, err := parseAddressedCallWarpLog(log)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	teleporterMessage, err := teleportermessenger.UnpackTeleporterMessage(addressedCall.Payload)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return unsignedMsg, addressedCall, teleporterMessage, nil
+}
+
+// addSourceDestinationFlags adds the flags needed by commands that interact with both the source and
+// destination chains of a message, and connects to both chains before the command is run.
+func addSourceDestinationFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&sourceRPCEndpoint, "source-rpc", "", "RPC endpoint of the source chain")
+	cmd.PersistentFlags().StringVar(&destinationRPCEndpoint, "destination-rpc", "",
+		"RPC endpoint of the destination chain")
+	address := cmd.PersistentFlags().StringP("teleporter-address", "t", "", "Teleporter contract address")
+	cmd.PersistentFlags().StringVar(&sourceChainName, "source-chain", "",
+		"Name of the source chain in the config file, used instead of --source-rpc and --teleporter-address")
+	cmd.PersistentFlags().StringVar(&destChainName, "destination-chain", "",
+		"Name of the destination chain in the config file, used instead of --destination-rpc")
+	err := cmd.MarkPersistentFlagRequired("source-rpc")
+	cobra.CheckErr(err)
+	err = cmd.MarkPersistentFlagRequired("destination-rpc")
+	cobra.CheckErr(err)
+	err = cmd.MarkPersistentFlagRequired("teleporter-address")
+	cobra.CheckErr(err)
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return sourceDestinationPreRunE(cmd, args, address)
+	}
+}
+
+func sourceDestinationPreRunE(cmd *cobra.Command, args []string, address *string) error {
+	// Run the persistent pre-run function of the root command if it exists.
+	if err := callPersistentPreRunE(cmd, args); err != nil {
+		return err
+	}
+	if err := setChainFlags(cmd, sourceChainName, "source-rpc", "teleporter-address"); err != nil {
+		return err
+	}
+	if err := setChainFlags(cmd, destChainName, "destination-rpc", ""); err != nil {
+		return err
+	}
+	teleporterAddress = common.HexToAddress(*address)
+	var err error
+	sourceClient, err = ethclient.Dial(sourceRPCEndpoint)
+	if err != nil {
+		return err
+	}
+	destinationClient, err = ethclient.Dial(destinationRPCEndpoint)
+	return err
+}
+
+// addRPCFlags adds the flags needed by commands that interact with the TeleporterMessenger contract
+// on a single chain, and connects to the chain before the command is run.
+func addRPCFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&rpcEndpoint, "rpc", "", "RPC endpoint to connect to the node")
+	address := cmd.PersistentFlags().StringP("teleporter-address", "t", "", "Teleporter contract address")
+	cmd.PersistentFlags().StringVar(&registryAddress, registryFlag, "",
+		"TeleporterRegistry contract address, used to look up the Teleporter address if --teleporter-address is not provided")
+	cmd.PersistentFlags().Uint64Var(&teleporterVersion, "teleporter-version", 0,
+		"Version of the Teleporter contract to look up in the registry (default latest)")
+	cmd.PersistentFlags().StringVar(&chainName, "chain", "",
+		"Name of the chain in the config file, used instead of --rpc and --teleporter-address")
+	err := cmd.MarkPersistentFlagRequired("rpc")
+	cobra.CheckErr(err)
+	cmd.MarkFlagsOneRequired("teleporter-address", registryFlag)
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return transactionPreRunE(cmd, args, address)
+	}
+}
@@ -0,0 +1,403 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	pChainURI             string
+	warpSigningSubnetID   string
+	warpPChainHeight      uint64
+	warpQuorumNumerator   uint64
+	errSignatureNotBitSet = errors.New("signature is not a BitSetSignature")
+)
+
+type warpSignatureOutput struct {
+	Signers    []int  `json:"signers" yaml:"signers"`
+	NumSigners int    `json:"numSigners" yaml:"numSigners"`
+	Signature  string `json:"signature" yaml:"signature"`
+}
+
+type addressedCallOutput struct {
+	SourceAddress string `json:"sourceAddress" yaml:"sourceAddress"`
+	Payload       string `json:"payload" yaml:"payload"`
+}
+
+type warpDecodeOutput struct {
+	WarpMessageID      string                   `json:"warpMessageID" yaml:"warpMessageID"`
+	NetworkID          uint32                   `json:"networkID" yaml:"networkID"`
+	SourceBlockchainID string                   `json:"sourceBlockchainID" yaml:"sourceBlockchainID"`
+	Signature          *warpSignatureOutput     `json:"signature,omitempty" yaml:"signature,omitempty"`
+	AddressedCall      *addressedCallOutput     `json:"addressedCall,omitempty" yaml:"addressedCall,omitempty"`
+	Message            *teleporterMessageOutput `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type warpSignerOutput struct {
+	Index     int      `json:"index" yaml:"index"`
+	NodeIDs   []string `json:"nodeIDs" yaml:"nodeIDs"`
+	PublicKey string   `json:"publicKey" yaml:"publicKey"`
+	Weight    uint64   `json:"weight" yaml:"weight"`
+}
+
+type warpVerifyOutput struct {
+	WarpMessageID      string             `json:"warpMessageID" yaml:"warpMessageID"`
+	SourceBlockchainID string             `json:"sourceBlockchainID" yaml:"sourceBlockchainID"`
+	SigningSubnetID    string             `json:"signingSubnetID" yaml:"signingSubnetID"`
+	PChainHeight       uint64             `json:"pChainHeight" yaml:"pChainHeight"`
+	NumValidators      int                `json:"numValidators" yaml:"numValidators"`
+	TotalWeight        uint64             `json:"totalWeight" yaml:"totalWeight"`
+	SignedWeight       uint64             `json:"signedWeight" yaml:"signedWeight"`
+	QuorumNumerator    uint64             `json:"quorumNumerator" yaml:"quorumNumerator"`
+	QuorumDenominator  uint64             `json:"quorumDenominator" yaml:"quorumDenominator"`
+	Signers            []warpSignerOutput `json:"signers" yaml:"signers"`
+	QuorumReached      bool               `json:"quorumReached" yaml:"quorumReached"`
+	SignatureValid     bool               `json:"signatureValid" yaml:"signatureValid"`
+	Valid              bool               `json:"valid" yaml:"valid"`
+	Error              string             `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+var warpCmd = &cobra.Command{
+	Use:   "warp",
+	Short: "Decodes and verifies Warp messages",
+	Long: `Decodes unsigned and signed Warp messages, and verifies the aggregate BLS
+signatures of signed Warp messages against the validator set of the signing subnet.`,
+	Args: cobra.NoArgs,
+}
+
+var warpDecodeCmd = &cobra.Command{
+	Use:   "decode MESSAGE_BYTES",
+	Short: "Decodes unsigned or signed Warp message bytes",
+	Long: `Given the hex encoded bytes of a signed or unsigned Warp message, this command
+decodes the network ID, source blockchain ID, the signers and aggregate signature
+of signed messages, the AddressedCall payload, and the Teleporter message contained
+in the payload, if any.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		b, err := parseHexBytes(args[0])
+		cobra.CheckErr(err)
+		out, err := decodeWarpMessage(b)
+		cobra.CheckErr(err)
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, out))
+			return
+		}
+		logger.Info("Warp message decoded",
+			zap.String("warpMessageID", out.WarpMessageID),
+			zap.Uint32("networkID", out.NetworkID),
+			zap.String("sourceBlockchainID", out.SourceBlockchainID),
+			zap.Any("signature", out.Signature),
+			zap.Any("addressedCall", out.AddressedCall),
+			zap.Any("message", out.Message))
+		cmd.Println("Warp decode command ran successfully")
+	},
+}
+
+var warpVerifyCmd = &cobra.Command{
+	Use:   "verify --p-chain-uri URI SIGNED_MESSAGE_BYTES",
+	Short: "Verifies the aggregate signature of a signed Warp message",
+	Long: `Given the hex encoded bytes of a signed Warp message, this command fetches the
+validator set of the signing subnet from the P-Chain, and checks that the signers
+of the BitSetSignature hold at least --quorum-numerator percent of the subnet's
+weight and that the aggregate BLS signature is valid. The signing subnet is the
+subnet validating the source blockchain unless --signing-subnet-id is provided,
+as is needed for messages from the C-Chain, which are signed by the validators of
+the receiving subnet. The validator set is read at the current P-Chain height
+unless --p-chain-height is provided.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		b, err := parseHexBytes(args[0])
+		cobra.CheckErr(err)
+		msg, err := avalancheWarp.ParseMessage(b)
+		cobra.CheckErr(err)
+
+		var subnetID ids.ID
+		if warpSigningSubnetID != "" {
+			subnetID, err = ids.FromString(warpSigningSubnetID)
+			cobra.CheckErr(err)
+		}
+
+		ctx := context.Background()
+		networkID, err := info.NewClient(pChainURI).GetNetworkID(ctx)
+		cobra.CheckErr(err)
+		if msg.NetworkID != networkID {
+			cobra.CheckErr(fmt.Errorf("message network ID %d does not match the P-Chain's network ID %d",
+				msg.NetworkID, networkID))
+		}
+
+		out, err := verifyWarpMessage(ctx, platformvm.NewClient(pChainURI), msg, subnetID, warpPChainHeight,
+			warpQuorumNumerator)
+		cobra.CheckErr(err)
+		if !isTableOutput() {
+			cobra.CheckErr(printOutput(cmd, out))
+		} else {
+			logger.Info("Warp message signature checked",
+				zap.String("warpMessageID", out.WarpMessageID),
+				zap.String("signingSubnetID", out.SigningSubnetID),
+				zap.Uint64("pChainHeight", out.PChainHeight),
+				zap.Int("numValidators", out.NumValidators),
+				zap.Int("numSigners", len(out.Signers)),
+				zap.Uint64("signedWeight", out.SignedWeight),
+				zap.Uint64("totalWeight", out.TotalWeight),
+				zap.Bool("quorumReached", out.QuorumReached),
+				zap.Bool("signatureValid", out.SignatureValid))
+		}
+		if !out.Valid {
+			cobra.CheckErr(fmt.Errorf("warp message signature is invalid: %s", out.Error))
+		}
+		if isTableOutput() {
+			cmd.Println("Warp verify command ran successfully")
+		}
+	},
+}
+
+// decodeWarpMessage decodes signed Warp message bytes, or unsigned Warp message bytes if they are not signed
+func decodeWarpMessage(b []byte) (*warpDecodeOutput, error) {
+	var (
+		unsignedMsg *avalancheWarp.UnsignedMessage
+		sigOut      *warpSignatureOutput
+	)
+	if signedMsg, err := avalancheWarp.ParseMessage(b); err == nil {
+		unsignedMsg = &signedMsg.UnsignedMessage
+		sig, ok := signedMsg.Signature.(*avalancheWarp.BitSetSignature)
+		if !ok {
+			return nil, errSignatureNotBitSet
+		}
+		sigOut, err = newWarpSignatureOutput(sig)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		unsignedMsg, err = avalancheWarp.ParseUnsignedMessage(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signed or unsigned Warp message: %w", err)
+		}
+	}
+
+	out := &warpDecodeOutput{
+		WarpMessageID:      unsignedMsg.ID().String(),
+		NetworkID:          unsignedMsg.NetworkID,
+		SourceBlockchainID: unsignedMsg.SourceChainID.String(),
+		Signature:          sigOut,
+	}
+	addressedCall, err := warpPayload.ParseAddressedCall(unsignedMsg.Payload)
+	if err != nil {
+		logger.Info("Warp message payload is not an AddressedCall", zap.Error(err))
+		return out, nil
+	}
+	out.AddressedCall = &addressedCallOutput{
+		SourceAddress: common.BytesToAddress(addressedCall.SourceAddress).Hex(),
+		Payload:       hexutil.Encode(addressedCall.Payload),
+	}
+	message, err := teleportermessenger.UnpackTeleporterMessage(addressedCall.Payload)
+	if err != nil {
+		logger.Info("AddressedCall payload is not a Teleporter message", zap.Error(err))
+		return out, nil
+	}
+	out.Message = newTeleporterMessageOutput(*message)
+	return out, nil
+}
+
+func newWarpSignatureOutput(sig *avalancheWarp.BitSetSignature) (*warpSignatureOutput, error) {
+	numSigners, err := sig.NumSigners()
+	if err != nil {
+		return nil, err
+	}
+	signerIndices := set.BitsFromBytes(sig.Signers)
+	signers := []int{}
+	for i := 0; i < signerIndices.BitLen(); i++ {
+		if signerIndices.Contains(i) {
+			signers = append(signers, i)
+		}
+	}
+	return &warpSignatureOutput{
+		Signers:    signers,
+		NumSigners: numSigners,
+		Signature:  hexutil.Encode(sig.Signature[:]),
+	}, nil
+}
+
+// pChainClient is the subset of the P-Chain API used to verify Warp message signatures.
+// It is implemented by platformvm.Client.
+type pChainClient interface {
+	GetHeight(ctx context.Context, options ...rpc.Option) (uint64, error)
+	ValidatedBy(ctx context.Context, blockchainID ids.ID, options ...rpc.Option) (ids.ID, error)
+	GetValidatorsAt(
+		ctx context.Context,
+		subnetID ids.ID,
+		height uint64,
+		options ...rpc.Option,
+	) (map[ids.NodeID]*validators.GetValidatorOutput, error)
+}
+
+// pChainValidatorState implements avalancheWarp.ValidatorState with the P-Chain API
+type pChainValidatorState struct {
+	client pChainClient
+}
+
+func (s pChainValidatorState) GetValidatorSet(
+	ctx context.Context,
+	height uint64,
+	subnetID ids.ID,
+) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	return s.client.GetValidatorsAt(ctx, subnetID, height)
+}
+
+// verifyWarpMessage checks the signed Warp message's BitSetSignature against the validator set of the signing
+// subnet, in the same way as avalancheWarp.BitSetSignature.Verify, and reports the signers, their weight and
+// the result of each check. The signing subnet is the subnet validating the source blockchain if subnetID is
+// empty, and the current P-Chain height is used if pChainHeight is zero. Signature failures are reported in
+// the output rather than returned as errors.
+func verifyWarpMessage(
+	ctx context.Context,
+	client pChainClient,
+	msg *avalancheWarp.Message,
+	subnetID ids.ID,
+	pChainHeight uint64,
+	quorumNumerator uint64,
+) (*warpVerifyOutput, error) {
+	if quorumNumerator == 0 || quorumNumerator > warp.WarpQuorumDenominator {
+		return nil, fmt.Errorf("quorum numerator must be between 1 and %d", warp.WarpQuorumDenominator)
+	}
+	sig, ok := msg.Signature.(*avalancheWarp.BitSetSignature)
+	if !ok {
+		return nil, errSignatureNotBitSet
+	}
+
+	var err error
+	if subnetID == ids.Empty {
+		subnetID, err = client.ValidatedBy(ctx, msg.SourceChainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subnet validating %s: %w", msg.SourceChainID, err)
+		}
+	}
+	if pChainHeight == 0 {
+		pChainHeight, err = client.GetHeight(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get P-Chain height: %w", err)
+		}
+	}
+	vdrs, totalWeight, err := avalancheWarp.GetCanonicalValidatorSet(
+		ctx, pChainValidatorState{client: client}, pChainHeight, subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &warpVerifyOutput{
+		WarpMessageID:      msg.ID().String(),
+		SourceBlockchainID: msg.SourceChainID.String(),
+		SigningSubnetID:    subnetID.String(),
+		PChainHeight:       pChainHeight,
+		NumValidators:      len(vdrs),
+		TotalWeight:        totalWeight,
+		QuorumNumerator:    quorumNumerator,
+		QuorumDenominator:  warp.WarpQuorumDenominator,
+		Signers:            []warpSignerOutput{},
+	}
+
+	// The signer bit set must not be zero padded, and must only reference validators in the canonical set
+	signerIndices := set.BitsFromBytes(sig.Signers)
+	if len(signerIndices.Bytes()) != len(sig.Signers) {
+		out.Error = avalancheWarp.ErrInvalidBitSet.Error()
+		return out, nil
+	}
+	signers, err := avalancheWarp.FilterValidators(signerIndices, vdrs)
+	if err != nil {
+		out.Error = err.Error()
+		return out, nil
+	}
+	for i, vdr := range vdrs {
+		if !signerIndices.Contains(i) {
+			continue
+		}
+		nodeIDs := make([]string, 0, len(vdr.NodeIDs))
+		for _, nodeID := range vdr.NodeIDs {
+			nodeIDs = append(nodeIDs, nodeID.String())
+		}
+		out.Signers = append(out.Signers, warpSignerOutput{
+			Index:     i,
+			NodeIDs:   nodeIDs,
+			PublicKey: hexutil.Encode(vdr.PublicKeyBytes),
+			Weight:    vdr.Weight,
+		})
+	}
+	out.SignedWeight, err = avalancheWarp.SumWeight(signers)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	weightErr := avalancheWarp.VerifyWeight(out.SignedWeight, totalWeight, quorumNumerator, warp.WarpQuorumDenominator)
+	out.QuorumReached = weightErr == nil
+	if weightErr != nil {
+		errs = append(errs, weightErr)
+	}
+	if err := verifyAggregateSignature(signers, sig, msg.UnsignedMessage.Bytes()); err != nil {
+		errs = append(errs, err)
+	} else {
+		out.SignatureValid = true
+	}
+	out.Valid = out.QuorumReached && out.SignatureValid
+	if err := errors.Join(errs...); err != nil {
+		out.Error = err.Error()
+	}
+	return out, nil
+}
+
+// verifyAggregateSignature checks the aggregate BLS signature against the aggregate public key of the signers
+func verifyAggregateSignature(
+	signers []*avalancheWarp.Validator,
+	sig *avalancheWarp.BitSetSignature,
+	unsignedBytes []byte,
+) error {
+	if len(signers) == 0 {
+		return fmt.Errorf("%w: no signers", avalancheWarp.ErrInvalidSignature)
+	}
+	aggSig, err := bls.SignatureFromBytes(sig.Signature[:])
+	if err != nil {
+		return fmt.Errorf("%w: %w", avalancheWarp.ErrParseSignature, err)
+	}
+	aggPubKey, err := avalancheWarp.AggregatePublicKeys(signers)
+	if err != nil {
+		return err
+	}
+	if !bls.Verify(aggPubKey, aggSig, unsignedBytes) {
+		return avalancheWarp.ErrInvalidSignature
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(warpCmd)
+	warpCmd.AddCommand(warpDecodeCmd, warpVerifyCmd)
+	warpVerifyCmd.Flags().StringVar(&pChainURI, "p-chain-uri", "",
+		"Base URI of a node serving the P-Chain and info APIs, such as http://127.0.0.1:9650")
+	warpVerifyCmd.Flags().StringVar(&warpSigningSubnetID, "signing-subnet-id", "",
+		"Subnet whose validators signed the message, the subnet validating the source blockchain by default")
+	warpVerifyCmd.Flags().Uint64Var(&warpPChainHeight, "p-chain-height", 0,
+		"P-Chain height to read the validator set at (default current height)")
+	warpVerifyCmd.Flags().Uint64Var(&warpQuorumNumerator, "quorum-numerator", warp.WarpDefaultQuorumNumerator,
+		"Quorum numerator out of 100 that the signers' weight must reach")
+	err := warpVerifyCmd.MarkFlagRequired("p-chain-uri")
+	cobra.CheckErr(err)
+}
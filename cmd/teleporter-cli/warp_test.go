@@ -0,0 +1,269 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/utils/set"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarpCmds(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "decode no args",
+			args: []string{"warp", "decode"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "verify no args",
+			args: []string{"warp", "verify"},
+			err:  fmt.Errorf("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "verify help",
+			args: []string{"warp", "verify", "--help"},
+			out:  "fetches the\nvalidator set of the signing subnet from the P-Chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func newTestUnsignedWarpMessage(t *testing.T, sourceChainID ids.ID) *avalancheWarp.UnsignedMessage {
+	messageBytes, err := teleportermessenger.PackTeleporterMessage(createTestTeleporterMessage())
+	require.NoError(t, err)
+	addressedCall, err := warpPayload.NewAddressedCall(common.HexToAddress("0xabcd").Bytes(), messageBytes)
+	require.NoError(t, err)
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(5, sourceChainID, addressedCall.Bytes())
+	require.NoError(t, err)
+	return unsignedMsg
+}
+
+// newTestWarpLog returns a log of the Warp precompile for a message sent by the source address with the payload
+func newTestWarpLog(t *testing.T, sourceChainID ids.ID, sourceAddress common.Address, payload []byte) *types.Log {
+	addressedCall, err := warpPayload.NewAddressedCall(sourceAddress.Bytes(), payload)
+	require.NoError(t, err)
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(5, sourceChainID, addressedCall.Bytes())
+	require.NoError(t, err)
+	topics, data, err := warp.PackSendWarpMessageEvent(
+		sourceAddress, common.Hash(unsignedMsg.ID()), unsignedMsg.Bytes())
+	require.NoError(t, err)
+	return &types.Log{Address: warp.ContractAddress, Topics: topics, Data: data}
+}
+
+func TestDecodeWarpMessage(t *testing.T) {
+	unsignedMsg := newTestUnsignedWarpMessage(t, ids.ID{1, 2, 3})
+	signedMsg, err := avalancheWarp.NewMessage(unsignedMsg, &avalancheWarp.BitSetSignature{
+		Signers:   set.NewBits(0, 2).Bytes(),
+		Signature: [bls.SignatureLen]byte{1},
+	})
+	require.NoError(t, err)
+	nonTeleporterMsg, err := avalancheWarp.NewUnsignedMessage(5, ids.ID{1, 2, 3}, []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	out, err := decodeWarpMessage(unsignedMsg.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, unsignedMsg.ID().String(), out.WarpMessageID)
+	require.Equal(t, uint32(5), out.NetworkID)
+	require.Equal(t, ids.ID{1, 2, 3}.String(), out.SourceBlockchainID)
+	require.Nil(t, out.Signature)
+	require.Equal(t, common.HexToAddress("0xabcd").Hex(), out.AddressedCall.SourceAddress)
+	require.Equal(t, "1", out.Message.MessageNonce)
+
+	out, err = decodeWarpMessage(signedMsg.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, unsignedMsg.ID().String(), out.WarpMessageID)
+	require.Equal(t, []int{0, 2}, out.Signature.Signers)
+	require.Equal(t, 2, out.Signature.NumSigners)
+	require.NotNil(t, out.Message)
+
+	out, err = decodeWarpMessage(nonTeleporterMsg.Bytes())
+	require.NoError(t, err)
+	require.Nil(t, out.AddressedCall)
+	require.Nil(t, out.Message)
+}
+
+// fakePChainClient serves a single validator set of a subnet at a single height
+type fakePChainClient struct {
+	height     uint64
+	subnetID   ids.ID
+	validators map[ids.NodeID]*validators.GetValidatorOutput
+}
+
+func (c *fakePChainClient) GetHeight(context.Context, ...rpc.Option) (uint64, error) {
+	return c.height, nil
+}
+
+func (c *fakePChainClient) ValidatedBy(context.Context, ids.ID, ...rpc.Option) (ids.ID, error) {
+	return c.subnetID, nil
+}
+
+func (c *fakePChainClient) GetValidatorsAt(
+	_ context.Context,
+	subnetID ids.ID,
+	height uint64,
+	_ ...rpc.Option,
+) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	if subnetID != c.subnetID || height != c.height {
+		return nil, fmt.Errorf("no validators of subnet %s at height %d", subnetID, height)
+	}
+	return c.validators, nil
+}
+
+func TestVerifyWarpMessage(t *testing.T) {
+	unsignedMsg := newTestUnsignedWarpMessage(t, ids.ID{1, 2, 3})
+	client := &fakePChainClient{
+		height:     10,
+		subnetID:   ids.ID{4, 5, 6},
+		validators: make(map[ids.NodeID]*validators.GetValidatorOutput),
+	}
+	keys := make(map[string]*bls.SecretKey)
+	for i, weight := range []uint64{50, 30, 20} {
+		sk, err := bls.NewSecretKey()
+		require.NoError(t, err)
+		pk := bls.PublicFromSecretKey(sk)
+		keys[string(bls.SerializePublicKey(pk))] = sk
+		nodeID := ids.NodeID{byte(i + 1)}
+		client.validators[nodeID] = &validators.GetValidatorOutput{NodeID: nodeID, PublicKey: pk, Weight: weight}
+	}
+	vdrs, _, err := avalancheWarp.GetCanonicalValidatorSet(
+		context.Background(), pChainValidatorState{client: client}, client.height, client.subnetID)
+	require.NoError(t, err)
+
+	// sign returns the signature of the given validators, referenced by their index in the canonical set
+	sign := func(msg []byte, indices ...int) *avalancheWarp.BitSetSignature {
+		sigs := []*bls.Signature{}
+		for _, i := range indices {
+			sigs = append(sigs, bls.Sign(keys[string(vdrs[i].PublicKeyBytes)], msg))
+		}
+		aggSig, err := bls.AggregateSignatures(sigs)
+		require.NoError(t, err)
+		sig := &avalancheWarp.BitSetSignature{Signers: set.NewBits(indices...).Bytes()}
+		copy(sig.Signature[:], bls.SignatureToBytes(aggSig))
+		return sig
+	}
+	indexOfWeight := func(weight uint64) int {
+		for i, vdr := range vdrs {
+			if vdr.Weight == weight {
+				return i
+			}
+		}
+		t.Fatalf("no validator with weight %d", weight)
+		return 0
+	}
+
+	var tests = []struct {
+		name           string
+		signature      *avalancheWarp.BitSetSignature
+		quorum         uint64
+		signedWeight   uint64
+		quorumReached  bool
+		signatureValid bool
+		err            string
+	}{
+		{
+			name:           "all validators",
+			signature:      sign(unsignedMsg.Bytes(), 0, 1, 2),
+			quorum:         67,
+			signedWeight:   100,
+			quorumReached:  true,
+			signatureValid: true,
+		},
+		{
+			name:           "quorum of validators",
+			signature:      sign(unsignedMsg.Bytes(), indexOfWeight(50), indexOfWeight(20)),
+			quorum:         67,
+			signedWeight:   70,
+			quorumReached:  true,
+			signatureValid: true,
+		},
+		{
+			name:           "insufficient weight",
+			signature:      sign(unsignedMsg.Bytes(), indexOfWeight(30), indexOfWeight(20)),
+			quorum:         67,
+			signedWeight:   50,
+			quorumReached:  false,
+			signatureValid: true,
+			err:            "signature weight is insufficient",
+		},
+		{
+			name:           "lower quorum",
+			signature:      sign(unsignedMsg.Bytes(), indexOfWeight(30), indexOfWeight(20)),
+			quorum:         50,
+			signedWeight:   50,
+			quorumReached:  true,
+			signatureValid: true,
+		},
+		{
+			name:           "wrong message signed",
+			signature:      sign([]byte("other message"), 0, 1, 2),
+			quorum:         67,
+			signedWeight:   100,
+			quorumReached:  true,
+			signatureValid: false,
+			err:            "signature is invalid",
+		},
+		{
+			name:      "unknown validator",
+			signature: &avalancheWarp.BitSetSignature{Signers: set.NewBits(0, 5).Bytes()},
+			quorum:    67,
+			err:       "unknown validator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := avalancheWarp.NewMessage(unsignedMsg, tt.signature)
+			require.NoError(t, err)
+
+			out, err := verifyWarpMessage(context.Background(), client, msg, ids.Empty, 0, tt.quorum)
+			require.NoError(t, err)
+			require.Equal(t, client.subnetID.String(), out.SigningSubnetID)
+			require.Equal(t, client.height, out.PChainHeight)
+			require.Equal(t, 3, out.NumValidators)
+			require.Equal(t, uint64(100), out.TotalWeight)
+			require.Equal(t, tt.signedWeight, out.SignedWeight)
+			require.Equal(t, tt.quorumReached, out.QuorumReached)
+			require.Equal(t, tt.signatureValid, out.SignatureValid)
+			require.Equal(t, tt.err == "", out.Valid)
+			require.Contains(t, out.Error, tt.err)
+		})
+	}
+
+	msg, err := avalancheWarp.NewMessage(unsignedMsg, sign(unsignedMsg.Bytes(), 0))
+	require.NoError(t, err)
+	_, err = verifyWarpMessage(context.Background(), client, msg, ids.ID{7}, 0, 67)
+	require.ErrorContains(t, err, "failed to fetch validator set")
+	_, err = verifyWarpMessage(context.Background(), client, msg, ids.Empty, 0, 101)
+	require.ErrorContains(t, err, "quorum numerator must be between 1 and 100")
+}
@@ -0,0 +1,400 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// warpMessageEventName is the name used to filter the Warp messages sent by the TeleporterMessenger contract
+	warpMessageEventName = "SendWarpMessage"
+
+	defaultPollInterval = 2 * time.Second
+)
+
+var (
+	watchFromBlock                uint64
+	watchPollInterval             time.Duration
+	watchEvents                   []string
+	watchDestinationBlockchainIDs []string
+	watchSenders                  []string
+)
+
+// watchFilter selects the events printed by the watch command. Empty filters match all events.
+type watchFilter struct {
+	events                   map[string]bool
+	destinationBlockchainIDs map[string]bool
+	senders                  map[string]bool
+}
+
+func newWatchFilter(events []string, destinationBlockchainIDs []string, senders []string) (*watchFilter, error) {
+	f := &watchFilter{
+		events:                   make(map[string]bool),
+		destinationBlockchainIDs: make(map[string]bool),
+		senders:                  make(map[string]bool),
+	}
+	for _, event := range events {
+		if event != warpMessageEventName {
+			if _, err := teleportermessenger.ToEvent(event); err != nil {
+				return nil, err
+			}
+		}
+		f.events[event] = true
+	}
+	for _, s := range destinationBlockchainIDs {
+		id, err := parseID(s)
+		if err != nil {
+			return nil, err
+		}
+		f.destinationBlockchainIDs[id.String()] = true
+	}
+	for _, s := range senders {
+		sender, err := parseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		f.senders[sender.Hex()] = true
+	}
+	return f, nil
+}
+
+// watchesWarpMessages returns true if Warp messages may match the filter
+func (f *watchFilter) watchesWarpMessages() bool {
+	return len(f.events) == 0 || f.events[warpMessageEventName]
+}
+
+func (f *watchFilter) matches(out *logOutput) bool {
+	if len(f.events) > 0 && !f.events[out.name()] {
+		return false
+	}
+	if len(f.destinationBlockchainIDs) > 0 && !f.destinationBlockchainIDs[out.destinationBlockchainID()] {
+		return false
+	}
+	if len(f.senders) > 0 {
+		message := out.message()
+		if message == nil || !f.senders[message.OriginSenderAddress] {
+			return false
+		}
+	}
+	return true
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch --rpc RPC_URL --teleporter-address CONTRACT_ADDRESS [--from-block BLOCK]",
+	Short: "Streams decoded Teleporter events as they are emitted",
+	Long: `Watches the TeleporterMessenger contract and the Warp precompile for new logs, and
+prints each decoded Teleporter event, and each Warp message sent by the
+TeleporterMessenger contract, as it arrives. If the RPC endpoint is a websocket
+endpoint, logs are received through a subscription, falling back to polling
+eth_getLogs every --poll-interval otherwise. After the subscription is dropped, the
+command reconnects and resumes from the last block it processed, so no events are
+missed. Events can be filtered by name (including SendWarpMessage for Warp
+messages), by destination blockchain ID, and by the origin sender address of their
+message. In json and yaml output formats, one document is printed per event.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, err := newWatchFilter(watchEvents, watchDestinationBlockchainIDs, watchSenders)
+		cobra.CheckErr(err)
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		fromBlock := watchFromBlock
+		if fromBlock == 0 {
+			latest, err := client.BlockNumber(ctx)
+			cobra.CheckErr(err)
+			fromBlock = latest + 1
+		}
+		addresses := []common.Address{teleporterAddress}
+		if filter.watchesWarpMessages() {
+			addresses = append(addresses, warp.ContractAddress)
+		}
+
+		w := &logWatcher{
+			client:       client,
+			addresses:    addresses,
+			fromBlock:    fromBlock,
+			pollInterval: watchPollInterval,
+			handle: func(log types.Log) error {
+				out, err := decodeLogOutput(log)
+				if err != nil {
+					logger.Warn("Failed to decode log",
+						zap.String("txHash", log.TxHash.Hex()),
+						zap.Uint("logIndex", log.Index),
+						zap.Error(err))
+					return nil
+				}
+				if out == nil || !filter.matches(out) {
+					return nil
+				}
+				return printLogOutput(cmd, out)
+			},
+		}
+		if isWebsocketEndpoint(rpcEndpoint) {
+			w.dial = func(ctx context.Context) (logReader, error) {
+				c, err := ethclient.DialContext(ctx, rpcEndpoint)
+				if err != nil {
+					return nil, err
+				}
+				return c, nil
+			}
+		}
+		logger.Info("Watching for Teleporter events", zap.Uint64("fromBlock", fromBlock))
+		cobra.CheckErr(w.run(ctx))
+		cmd.Println("Watch command ran successfully")
+	},
+}
+
+func printLogOutput(cmd *cobra.Command, out *logOutput) error {
+	if !isTableOutput() {
+		return printStreamOutput(cmd, out)
+	}
+	fields := []zap.Field{
+		zap.Uint64("blockNumber", out.BlockNumber),
+		zap.String("txHash", out.TransactionHash),
+	}
+	if out.Removed {
+		fields = append(fields, zap.Bool("removed", true))
+	}
+	if out.Event != nil {
+		logger.Info(out.Event.Name, append(fields, zap.Any("event", out.Event))...)
+	} else {
+		logger.Info(warpMessageEventName, append(fields, zap.Any("warpMessage", out.WarpMessage))...)
+	}
+	return nil
+}
+
+func isWebsocketEndpoint(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "ws://") || strings.HasPrefix(endpoint, "wss://")
+}
+
+// logReader is the subset of an RPC client needed to watch for logs
+type logReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q interfaces.FilterQuery) ([]types.Log, error)
+	SubscribeFilterLogs(ctx context.Context, q interfaces.FilterQuery, ch chan<- types.Log) (interfaces.Subscription, error)
+}
+
+// logWatcher passes the logs emitted by the addresses to handle, in order and exactly once,
+// starting from fromBlock. If dial is set, logs are received through a subscription, and the
+// client is redialed after the subscription is dropped. Otherwise, logs are polled.
+type logWatcher struct {
+	client       logReader
+	dial         func(ctx context.Context) (logReader, error)
+	addresses    []common.Address
+	fromBlock    uint64
+	pollInterval time.Duration
+	handle       func(log types.Log) error
+
+	// Whether a subscription was ever created, in which case failing to subscribe again is retried
+	subscribed bool
+	// The position of the last handled log, used to skip logs that are received twice
+	// when logs are fetched again after resuming.
+	handled   bool
+	lastBlock uint64
+	lastIndex uint
+}
+
+// run watches for logs until the context is cancelled
+func (w *logWatcher) run(ctx context.Context) error {
+	if w.dial == nil {
+		return w.poll(ctx)
+	}
+	for {
+		err := w.subscribe(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == errSubscriptionUnsupported {
+			logger.Warn("Log subscriptions are not supported, falling back to polling")
+			return w.poll(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		logger.Warn("Log subscription dropped, reconnecting", zap.Uint64("fromBlock", w.fromBlock))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.pollInterval):
+		}
+		client, err := w.dial(ctx)
+		if err != nil {
+			logger.Warn("Failed to reconnect", zap.Error(err))
+			continue
+		}
+		w.client = client
+	}
+}
+
+var errSubscriptionUnsupported = fmt.Errorf("log subscriptions are not supported")
+
+// subscribe subscribes to new logs, fetches the logs emitted since fromBlock, then handles new logs
+// as they arrive. It returns a nil error once the subscription is dropped.
+func (w *logWatcher) subscribe(ctx context.Context) error {
+	logs := make(chan types.Log)
+	sub, err := w.client.SubscribeFilterLogs(ctx, interfaces.FilterQuery{Addresses: w.addresses}, logs)
+	if err != nil {
+		if !w.subscribed {
+			return errSubscriptionUnsupported
+		}
+		logger.Warn("Failed to subscribe to logs", zap.Error(err))
+		return nil
+	}
+	defer sub.Unsubscribe()
+	w.subscribed = true
+
+	// Catch up on the logs emitted before the subscription was created.
+	// Logs received through the subscription in the meantime are buffered by the channel's sender.
+	if err := w.catchUp(ctx); err != nil {
+		if err, ok := err.(handleError); ok {
+			return err.err
+		}
+		logger.Warn("Failed to fetch logs", zap.Error(err))
+		return nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			if err != nil {
+				logger.Warn("Log subscription failed", zap.Error(err))
+			}
+			return nil
+		case log := <-logs:
+			if err := w.handleLog(log); err != nil {
+				return err
+			}
+			// Logs in the rest of this block may not have been received yet
+			w.fromBlock = log.BlockNumber
+		}
+	}
+}
+
+// poll fetches the logs emitted since fromBlock every pollInterval
+func (w *logWatcher) poll(ctx context.Context) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := w.catchUp(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err, ok := err.(handleError); ok {
+				return err.err
+			}
+			logger.Warn("Failed to fetch logs", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleError wraps errors returned by handle, to distinguish them from RPC errors that can be retried
+type handleError struct {
+	err error
+}
+
+func (e handleError) Error() string {
+	return e.err.Error()
+}
+
+// catchUp fetches and handles the logs emitted from fromBlock up to the latest block
+func (w *logWatcher) catchUp(ctx context.Context) error {
+	latest, err := w.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if latest < w.fromBlock {
+		return nil
+	}
+	logs, err := w.client.FilterLogs(ctx, interfaces.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(w.fromBlock),
+		ToBlock:   new(big.Int).SetUint64(latest),
+		Addresses: w.addresses,
+	})
+	if err != nil {
+		return err
+	}
+	for _, log := range logs {
+		if err := w.handleLog(log); err != nil {
+			return handleError{err}
+		}
+	}
+	w.fromBlock = latest + 1
+	return nil
+}
+
+// handleLog handles the log unless it was already handled. Logs removed by a reorg are always handled, and
+// rewind the position of the last handled log to just before the removed log, so that the logs replacing it
+// are handled.
+func (w *logWatcher) handleLog(log types.Log) error {
+	if log.Removed {
+		w.rewind(log)
+		return w.handle(log)
+	}
+	if w.handled && (log.BlockNumber < w.lastBlock || (log.BlockNumber == w.lastBlock && log.Index <= w.lastIndex)) {
+		return nil
+	}
+	w.handled, w.lastBlock, w.lastIndex = true, log.BlockNumber, log.Index
+	return w.handle(log)
+}
+
+// rewind moves the position of the last handled log, and the block logs are fetched from after resuming,
+// back to just before the removed log
+func (w *logWatcher) rewind(removed types.Log) {
+	if removed.BlockNumber < w.fromBlock {
+		w.fromBlock = removed.BlockNumber
+	}
+	if !w.handled || removed.BlockNumber > w.lastBlock ||
+		(removed.BlockNumber == w.lastBlock && removed.Index > w.lastIndex) {
+		return
+	}
+	switch {
+	case removed.Index > 0:
+		w.lastBlock, w.lastIndex = removed.BlockNumber, removed.Index-1
+	case removed.BlockNumber > 0:
+		// Log indexes are positions in the block, so every log of the previous block is before the removed log
+		w.lastBlock, w.lastIndex = removed.BlockNumber-1, math.MaxUint
+	default:
+		w.handled = false
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	addRPCFlags(watchCmd)
+	watchCmd.Annotations = map[string]string{preferWebsocketAnnotation: "true"}
+	watchCmd.Flags().Uint64Var(&watchFromBlock, "from-block", 0,
+		"Block to start watching from, the next block by default")
+	watchCmd.Flags().DurationVar(&watchPollInterval, "poll-interval", defaultPollInterval,
+		"Interval between polls when log subscriptions are unavailable, and between reconnection attempts")
+	watchCmd.Flags().StringSliceVar(&watchEvents, "event", nil,
+		"Names of the events to print, such as SendCrossChainMessage or SendWarpMessage")
+	watchCmd.Flags().StringSliceVar(&watchDestinationBlockchainIDs, "destination-blockchain-id", nil,
+		"Only print events for messages to these blockchain IDs")
+	watchCmd.Flags().StringSliceVar(&watchSenders, "sender", nil,
+		"Only print events for messages sent by these origin sender addresses")
+}
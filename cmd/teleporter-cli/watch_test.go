@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+		err  error
+		out  string
+	}{
+		{
+			name: "args",
+			args: []string{"watch", "arg"},
+			err:  fmt.Errorf("unknown command \"arg\" for \"teleporter-cli watch\""),
+		},
+		{
+			name: "help",
+			args: []string{"watch", "--help"},
+			err:  nil,
+			out:  "Watches the TeleporterMessenger contract and the Warp precompile for new logs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeTestCmd(t, rootCmd, tt.args...)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Contains(t, out, tt.out)
+			}
+		})
+	}
+}
+
+func TestWatchFilter(t *testing.T) {
+	destinationBlockchainID := ids.GenerateTestID()
+	message := newTeleporterMessageOutput(createTestTeleporterMessage())
+	message.DestinationBlockchainID = destinationBlockchainID.String()
+	sender := message.OriginSenderAddress
+
+	sent := &logOutput{Event: &eventOutput{
+		Name:                    "SendCrossChainMessage",
+		DestinationBlockchainID: destinationBlockchainID.String(),
+		Message:                 message,
+	}}
+	received := &logOutput{Event: &eventOutput{
+		Name:    "ReceiveCrossChainMessage",
+		Message: message,
+	}}
+	executed := &logOutput{Event: &eventOutput{Name: "MessageExecuted"}}
+	warpMessage := &logOutput{WarpMessage: &warpMessageOutput{Message: message}}
+
+	var tests = []struct {
+		name                     string
+		events                   []string
+		destinationBlockchainIDs []string
+		senders                  []string
+		matches                  []*logOutput
+	}{
+		{
+			name:    "no filters",
+			matches: []*logOutput{sent, received, executed, warpMessage},
+		},
+		{
+			name:    "events",
+			events:  []string{"MessageExecuted", "SendWarpMessage"},
+			matches: []*logOutput{executed, warpMessage},
+		},
+		{
+			name:                     "destination blockchain ID",
+			destinationBlockchainIDs: []string{destinationBlockchainID.Hex()},
+			matches:                  []*logOutput{sent, received, warpMessage},
+		},
+		{
+			name:                     "other destination blockchain ID",
+			destinationBlockchainIDs: []string{ids.GenerateTestID().String()},
+			matches:                  []*logOutput{},
+		},
+		{
+			name:    "sender",
+			events:  []string{"SendCrossChainMessage", "MessageExecuted"},
+			senders: []string{sender},
+			matches: []*logOutput{sent},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newWatchFilter(tt.events, tt.destinationBlockchainIDs, tt.senders)
+			require.NoError(t, err)
+			matches := []*logOutput{}
+			for _, out := range []*logOutput{sent, received, executed, warpMessage} {
+				if filter.matches(out) {
+					matches = append(matches, out)
+				}
+			}
+			require.Equal(t, tt.matches, matches)
+		})
+	}
+
+	_, err := newWatchFilter([]string{"NotAnEvent"}, nil, nil)
+	require.Error(t, err)
+}
+
+// fakeLogReader serves the logs up to latest, and sends subscriptionLogs through subscriptions.
+// If dropSubscription is set, subscriptions fail once all of their logs are sent.
+type fakeLogReader struct {
+	logs                     []types.Log
+	latest                   uint64
+	subscriptionLogs         []types.Log
+	dropSubscription         bool
+	subscriptionsUnsupported bool
+}
+
+func (r *fakeLogReader) BlockNumber(context.Context) (uint64, error) {
+	return r.latest, nil
+}
+
+func (r *fakeLogReader) FilterLogs(_ context.Context, q interfaces.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range r.logs {
+		if log.BlockNumber >= q.FromBlock.Uint64() && log.BlockNumber <= q.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (r *fakeLogReader) SubscribeFilterLogs(
+	_ context.Context,
+	_ interfaces.FilterQuery,
+	ch chan<- types.Log,
+) (interfaces.Subscription, error) {
+	if r.subscriptionsUnsupported {
+		return nil, fmt.Errorf("notifications not supported")
+	}
+	sub := &fakeSubscription{err: make(chan error, 1), quit: make(chan struct{})}
+	go func() {
+		for _, log := range r.subscriptionLogs {
+			select {
+			case ch <- log:
+			case <-sub.quit:
+				return
+			}
+		}
+		if r.dropSubscription {
+			sub.err <- fmt.Errorf("connection closed")
+		}
+	}()
+	return sub, nil
+}
+
+type fakeSubscription struct {
+	err  chan error
+	quit chan struct{}
+	once sync.Once
+}
+
+func (s *fakeSubscription) Err() <-chan error {
+	return s.err
+}
+
+func (s *fakeSubscription) Unsubscribe() {
+	s.once.Do(func() { close(s.quit) })
+}
+
+func TestLogWatcher(t *testing.T) {
+	newLog := func(blockNumber uint64, index uint) types.Log {
+		return types.Log{BlockNumber: blockNumber, Index: index}
+	}
+	allLogs := []types.Log{newLog(1, 0), newLog(2, 1), newLog(3, 2), newLog(4, 3), newLog(4, 4)}
+
+	// A log of block 3 removed by a reorg, and the log replacing it at the same position
+	removedLog := newLog(3, 2)
+	removedLog.Removed = true
+	replacementLog := newLog(3, 2)
+	replacementLog.TxHash = common.Hash{1}
+
+	var tests = []struct {
+		name      string
+		subscribe bool
+		readers   []*fakeLogReader
+		handled   []types.Log
+	}{
+		{
+			name:    "poll",
+			readers: []*fakeLogReader{{logs: allLogs, latest: 4}},
+			handled: allLogs,
+		},
+		{
+			name:      "subscriptions unsupported",
+			subscribe: true,
+			readers: []*fakeLogReader{
+				{logs: allLogs, latest: 4, subscriptionsUnsupported: true},
+			},
+			handled: allLogs,
+		},
+		{
+			name:      "resubscribe",
+			subscribe: true,
+			readers: []*fakeLogReader{
+				{
+					logs:             allLogs[:2],
+					latest:           2,
+					subscriptionLogs: []types.Log{newLog(2, 1), newLog(3, 2)},
+					dropSubscription: true,
+				},
+				{
+					logs:   allLogs,
+					latest: 4,
+				},
+			},
+			handled: allLogs,
+		},
+		{
+			name:      "reorg",
+			subscribe: true,
+			readers: []*fakeLogReader{
+				{
+					logs:   allLogs[:2],
+					latest: 2,
+					subscriptionLogs: []types.Log{
+						newLog(3, 2), removedLog, replacementLog, newLog(4, 3), newLog(4, 4),
+					},
+				},
+			},
+			handled: []types.Log{
+				newLog(1, 0), newLog(2, 1), newLog(3, 2), removedLog, replacementLog, newLog(4, 3), newLog(4, 4),
+			},
+		},
+		{
+			name:      "reorg before resubscribing",
+			subscribe: true,
+			readers: []*fakeLogReader{
+				{
+					logs:             allLogs[:3],
+					latest:           3,
+					subscriptionLogs: []types.Log{removedLog},
+					dropSubscription: true,
+				},
+				{
+					logs:   []types.Log{newLog(1, 0), newLog(2, 1), replacementLog, newLog(4, 3), newLog(4, 4)},
+					latest: 4,
+				},
+			},
+			handled: []types.Log{
+				newLog(1, 0), newLog(2, 1), newLog(3, 2), removedLog, replacementLog, newLog(4, 3), newLog(4, 4),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			var handled []types.Log
+			w := &logWatcher{
+				client:       tt.readers[0],
+				fromBlock:    1,
+				pollInterval: 10 * time.Millisecond,
+				handle: func(log types.Log) error {
+					handled = append(handled, log)
+					if len(handled) == len(tt.handled) {
+						cancel()
+					}
+					return nil
+				},
+			}
+			if tt.subscribe {
+				dials := 0
+				w.dial = func(context.Context) (logReader, error) {
+					dials++
+					return tt.readers[dials], nil
+				}
+			}
+			require.NoError(t, w.run(ctx))
+			require.Equal(t, tt.handled, handled)
+		})
+	}
+}
+
+func TestIsWebsocketEndpoint(t *testing.T) {
+	require.True(t, isWebsocketEndpoint("ws://127.0.0.1:9650/ext/bc/C/ws"))
+	require.True(t, isWebsocketEndpoint("wss://api.avax-test.network/ext/bc/C/ws"))
+	require.False(t, isWebsocketEndpoint("http://127.0.0.1:9650/ext/bc/C/rpc"))
+	require.False(t, isWebsocketEndpoint("https://api.avax-test.network/ext/bc/C/rpc"))
+}